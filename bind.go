@@ -0,0 +1,279 @@
+package goenum
+
+import (
+	"encoding"
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lookup The value source of Bind. Return all raw values of the key, and whether the key exists
+type Lookup func(key string) ([]string, bool)
+
+// EnvLookup Lookup environment variables
+func EnvLookup(key string) ([]string, bool) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil, false
+	}
+	return []string{v}, true
+}
+
+// MapLookup Lookup values in a map[string]string
+func MapLookup(m map[string]string) Lookup {
+	return func(key string) ([]string, bool) {
+		v, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+		return []string{v}, true
+	}
+}
+
+// FieldError The value of a struct field can not be decoded
+type FieldError struct {
+	// Field Path of the field, such as Config.Trade.State
+	Field string
+	// Key The key of the value in the source
+	Key string
+	// Value The raw value
+	Value string
+	// Allowed The allowed enumeration names, empty if the field is not an enumeration or EnumSet
+	Allowed []string
+	// Err The underlying error
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	msg := e.Field + ": invalid value " + strconv.Quote(e.Value)
+	if e.Key != "" {
+		msg += " of " + e.Key
+	}
+	if len(e.Allowed) > 0 {
+		msg += ", allowed: " + strings.Join(e.Allowed, ", ")
+	} else if e.Err != nil {
+		msg += ", " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BindError Aggregate all invalid fields found by Bind
+type BindError struct {
+	Errors []*FieldError
+}
+
+func (e *BindError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// BindEnv Bind environment variables to the struct pointed to by dst, see Bind. Fields are tagged with `env:"NAME"`
+func BindEnv(dst any) error {
+	return Bind(dst, "env", EnvLookup)
+}
+
+// BindMap Bind a map[string]string to the struct pointed to by dst, see Bind. Fields are tagged with `env:"NAME"`
+func BindMap(dst any, m map[string]string) error {
+	return Bind(dst, "env", MapLookup(m))
+}
+
+// Bind Walk the struct pointed to by dst, and decode the values found by lookup into the fields tagged with tag.
+// Enumeration fields are decoded by name (case-insensitive), EnumSet and slice fields accept comma-separated names
+// or repeated values. Basic kinds, time.Duration and encoding.TextUnmarshaler are supported as well.
+// The tag `default:"Paid"` gives the value used when the key is not found.
+// Nested struct fields without tag are walked recursively.
+// All invalid values are reported together by a *BindError
+func Bind(dst any, tag string, lookup Lookup) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("goenum: Bind requires a non-nil pointer to struct")
+	}
+	var fieldErrors []*FieldError
+	bindStruct(v.Elem(), v.Elem().Type().Name(), tag, lookup, &fieldErrors)
+	if len(fieldErrors) > 0 {
+		return &BindError{Errors: fieldErrors}
+	}
+	return nil
+}
+
+func bindStruct(v reflect.Value, path string, tag string, lookup Lookup, fieldErrors *[]*FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+		fieldPath := path + "." + sf.Name
+		key, tagged := sf.Tag.Lookup(tag)
+		if !tagged || key == "-" {
+			if key != "-" && sf.Type.Kind() == reflect.Struct && !isEnumType(sf.Type) {
+				bindStruct(fv, fieldPath, tag, lookup, fieldErrors)
+			}
+			continue
+		}
+		values, found := lookup(key)
+		if !found {
+			def, hasDefault := sf.Tag.Lookup("default")
+			if !hasDefault {
+				continue
+			}
+			values = []string{def}
+		}
+		if fe := decodeField(fv, values); fe != nil {
+			fe.Field, fe.Key = fieldPath, key
+			*fieldErrors = append(*fieldErrors, fe)
+		}
+	}
+}
+
+// decodeField Decode the raw values into the field, return nil if succeed
+func decodeField(fv reflect.Value, values []string) *FieldError {
+	raw := strings.Join(values, ",")
+	if isEnumType(fv.Type()) {
+		e, valid := valueOfType(fv.Type(), strings.TrimSpace(raw))
+		if !valid {
+			return &FieldError{Value: raw, Allowed: namesOfType(fv.Type())}
+		}
+		fv.Set(reflect.ValueOf(e))
+		return nil
+	}
+	if set, ok := newEnumSetOf(fv); ok {
+		var invalid []string
+		for _, name := range splitValues(values) {
+			e, valid := valueOfType(set.enumType(), name)
+			if !valid {
+				invalid = append(invalid, name)
+				continue
+			}
+			set.addEnum(e)
+		}
+		if len(invalid) > 0 {
+			return &FieldError{Value: strings.Join(invalid, ","), Allowed: namesOfType(set.enumType())}
+		}
+		fv.Set(reflect.ValueOf(set))
+		return nil
+	}
+	if fv.Kind() == reflect.Slice && isEnumType(fv.Type().Elem()) {
+		elemType := fv.Type().Elem()
+		res := reflect.MakeSlice(fv.Type(), 0, len(values))
+		var invalid []string
+		for _, name := range splitValues(values) {
+			e, valid := valueOfType(elemType, name)
+			if !valid {
+				invalid = append(invalid, name)
+				continue
+			}
+			res = reflect.Append(res, reflect.ValueOf(e))
+		}
+		if len(invalid) > 0 {
+			return &FieldError{Value: strings.Join(invalid, ","), Allowed: namesOfType(elemType)}
+		}
+		fv.Set(res)
+		return nil
+	}
+	if err := decodeBasic(fv, raw); err != nil {
+		return &FieldError{Value: raw, Err: err}
+	}
+	return nil
+}
+
+// newEnumSetOf Create an empty EnumSet of the type held by the field, which is assigned to the field only if all values
+// are valid, like slice fields. The field is a *UnsafeEnumSet, or an interface holding one
+func newEnumSetOf(fv reflect.Value) (enumSetBinder, bool) {
+	if fv.Kind() == reflect.Interface && !fv.IsNil() {
+		set, ok := fv.Interface().(enumSetBinder)
+		if !ok {
+			return nil, false
+		}
+		return set.newSet(), true
+	}
+	binder, ok := reflect.Zero(fv.Type()).Interface().(enumSetBinder)
+	if !ok || fv.Kind() != reflect.Ptr {
+		return nil, false
+	}
+	return binder.newSet(), true
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func decodeBasic(fv reflect.Value, raw string) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return decodeBasic(fv.Elem(), raw)
+	}
+	if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err == nil {
+			fv.SetInt(int64(d))
+		}
+		return err
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 0, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		parts := splitValues([]string{raw})
+		res := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := decodeBasic(res.Index(i), part); err != nil {
+				return err
+			}
+		}
+		fv.Set(res)
+	default:
+		return errors.New("unsupported field type " + fv.Type().String())
+	}
+	return nil
+}
+
+// splitValues Split comma-separated values, blank values are dropped
+func splitValues(values []string) (res []string) {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				res = append(res, part)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,162 @@
+package goenum
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// typeCache The index of the members of the type T in a registry, built once per T and rebuilt after the
+// registry or its ancestors change
+type typeCache[T EnumDefinition] struct {
+	// snapshots The snapshots of the registry and its ancestors the index is built from
+	snapshots []*registry
+	// values Members sorted by ordinal, shared by all readers and never modified
+	values []T
+	byName map[string]T
+	// byFoldedName Members keyed by foldKey of their names, for ValueOfIgnoreCase
+	byFoldedName map[string]T
+	// isInterface T is an interface, members of any type implementing T are matched by name
+	isInterface bool
+	isPointer   bool
+	typeName    string
+}
+
+// cacheKey The key of the typeCache of T in Registry.caches, a typed nil pointer does not allocate
+func cacheKey[T EnumDefinition]() any {
+	return (*T)(nil)
+}
+
+// cacheOf Get the index of T in reg, rebuild it if outdated
+func cacheOf[T EnumDefinition](reg *Registry) *typeCache[T] {
+	if c, ok := reg.caches.Load(cacheKey[T]()); ok {
+		cache := c.(*typeCache[T])
+		if cache.isValid(reg) {
+			return cache
+		}
+	}
+	cache := buildCache[T](reg)
+	reg.caches.Store(cacheKey[T](), cache)
+	return cache
+}
+
+func (c *typeCache[T]) isValid(reg *Registry) bool {
+	i := 0
+	for ; reg != nil; reg = reg.parent {
+		if i >= len(c.snapshots) || c.snapshots[i] != reg.load() {
+			return false
+		}
+		i++
+	}
+	return i == len(c.snapshots)
+}
+
+// ordinalOf The ordinal of e, return false if e is not a member in the index.
+// Members of a struct type are matched by name at their ordinal, which does not box e like Equals
+func (c *typeCache[T]) ordinalOf(e T) (int, bool) {
+	if c.isInterface {
+		return e.Ordinal(), true
+	}
+	if c.isPointer && any(e) == any(*new(T)) {
+		return 0, false
+	}
+	ordinal := e.Ordinal()
+	return ordinal, ordinal >= 0 && ordinal < len(c.values) && e.Name() != "" && c.values[ordinal].Name() == e.Name()
+}
+
+// mustOrdinalOf Return the ordinal of e, or panic if e is not a member in the index,
+// such as the zero value, a nil pointer or an instance not created by NewEnum
+func (c *typeCache[T]) mustOrdinalOf(e T) int {
+	if c.isPointer && any(e) == any(*new(T)) {
+		panic("goenum: nil " + c.typeName + " is not a registered member")
+	}
+	ordinal, ok := c.ordinalOf(e)
+	if !ok {
+		panic("goenum: " + strconv.Quote(e.Name()) + " is not a registered member of " + c.typeName)
+	}
+	return ordinal
+}
+
+// foldKey The case folding key of s, two names have the same key if and only if strings.EqualFold reports them equal.
+// Each rune is replaced by the smallest rune of its unicode.SimpleFold orbit, such as K for k and the Kelvin sign K
+func foldKey(s string) string {
+	ascii := true
+	for i := 0; i < len(s) && ascii; i++ {
+		ascii = s[i] < utf8.RuneSelf
+	}
+	if ascii {
+		// ASCII字母所在折叠轨道的最小字符都是大写字母
+		return strings.ToUpper(s)
+	}
+	return strings.Map(func(r rune) rune {
+		min := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < min {
+				min = f
+			}
+		}
+		return min
+	}, s)
+}
+
+func buildCache[T EnumDefinition](reg *Registry) *typeCache[T] {
+	cache := &typeCache[T]{byName: make(map[string]T), byFoldedName: make(map[string]T)}
+	// 先记录快照再读取成员，并发注册时缓存只会被判定为过期，不会读到比快照更旧的成员
+	for r := reg; r != nil; r = r.parent {
+		cache.snapshots = append(cache.snapshots, r.load())
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	cache.typeName, cache.isPointer = typeKey(t), t.Kind() == reflect.Ptr
+	if t.Kind() == reflect.Interface {
+		// 接口类型没有注册的成员，保持原有语义：按名称匹配实现了该接口的任意成员
+		cache.isInterface = true
+		return cache
+	}
+	for _, e := range reg.values(typeKey(t)) {
+		if v, ok := e.(T); ok {
+			cache.values = append(cache.values, v)
+			cache.byName[v.Name()] = v
+			folded := foldKey(v.Name())
+			if _, exist := cache.byFoldedName[folded]; !exist {
+				cache.byFoldedName[folded] = v
+			}
+		}
+	}
+	cache.values = cache.values[:len(cache.values):len(cache.values)]
+	return cache
+}
+
+// ValueOfOrdinal Find the enumeration instance by ordinal, and return a zero value if out of range
+func ValueOfOrdinal[T EnumDefinition](ordinal int) (t T, valid bool) {
+	return ValueOfOrdinalIn[T](defaultRegistry, ordinal)
+}
+
+// ValueOfOrdinalIn Find the enumeration instance by ordinal in the registry reg and its ancestors
+func ValueOfOrdinalIn[T EnumDefinition](reg *Registry, ordinal int) (t T, valid bool) {
+	values := cacheOf[T](reg).values
+	if ordinal < 0 || ordinal >= len(values) {
+		return
+	}
+	if v := values[ordinal]; v.Ordinal() == ordinal {
+		return v, true
+	}
+	for _, v := range values {
+		if v.Ordinal() == ordinal {
+			return v, true
+		}
+	}
+	return
+}
+
+// ValuesView Return all enumeration instances sorted by ordinal, like Values, without copying.
+// The returned slice is shared and must not be modified, appending to it is safe
+func ValuesView[T EnumDefinition]() []T {
+	return ValuesViewIn[T](defaultRegistry)
+}
+
+// ValuesViewIn Return all enumeration instances in the registry reg and its ancestors without copying, see ValuesView
+func ValuesViewIn[T EnumDefinition](reg *Registry) []T {
+	return cacheOf[T](reg).values
+}
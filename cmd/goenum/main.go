@@ -0,0 +1,196 @@
+// Command goenum Generate documents and code of other languages from the enumerations registered by Go packages.
+//
+// The enumerations only exist after the init of their packages, so goenum generates a temporary
+// registrar program importing the target packages inside the current module, and runs it with go run.
+//
+// Usage:
+//
+//	goenum openapi [-o file] <import/path.StructType>...
+//	goenum export -lang typescript|python|java [-o dir] [-java-package name] <package>...
+//	goenum lock [-o file] <package>...
+//	goenum check [-lock file] [-json] <package>...
+//
+// Packages are import paths or patterns relative to the current directory, such as ./internal/..., resolved by go list.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/lvyahui8/goenum"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const usage = `Usage:
+  goenum openapi [-o file] <import/path.StructType>...
+        Generate the OpenAPI components of the struct types and their enumeration fields
+  goenum export -lang typescript|python|java [-o dir] [-java-package name] <package>...
+        Generate the enumeration types declared in the packages in another language
+  goenum lock [-o file] <package>...
+        Dump the schema of the enumeration types declared in the packages to a lockfile
+  goenum check [-lock file] [-json] <package>...
+        Compare the enumeration types declared in the packages with the lockfile, exit with 1 on breaking changes
+
+Packages are import paths or relative patterns such as ./internal/...
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "openapi":
+		err = runOpenAPI(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "lock":
+		err = runLock(os.Args[2:])
+	case "check":
+		err = runCheck(os.Args[2:], os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "goenum:", err)
+		os.Exit(1)
+	}
+}
+
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ExitOnError)
+	out := fs.String("o", "", "output file, default stdout")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("no struct type specified")
+	}
+	program, err := openAPIProgram(fs.Args())
+	if err != nil {
+		return err
+	}
+	data, err := runProgram(program)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, data)
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	lang := fs.String("lang", "typescript", "target language: typescript, python or java")
+	out := fs.String("o", ".", "output directory")
+	javaPackage := fs.String("java-package", "", "package prefix of the generated Java files")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("no package specified")
+	}
+	pkgPaths, err := resolvePackages(fs.Args())
+	if err != nil {
+		return err
+	}
+	program, err := exportProgram(goenum.ExportOptions{Lang: *lang, PkgPaths: pkgPaths, JavaPackage: *javaPackage})
+	if err != nil {
+		return err
+	}
+	data, err := runProgram(program)
+	if err != nil {
+		return err
+	}
+	var files []goenum.ExportFile
+	if err = json.Unmarshal(data, &files); err != nil {
+		return err
+	}
+	for _, f := range files {
+		file := filepath.Join(*out, filepath.FromSlash(f.Path))
+		if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err = os.WriteFile(file, f.Content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runLock(args []string) error {
+	fs := flag.NewFlagSet("lock", flag.ExitOnError)
+	out := fs.String("o", "goenum.lock.json", "output file, - for stdout")
+	_ = fs.Parse(args)
+	data, err := snapshot(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *out == "-" {
+		*out = ""
+	}
+	return writeOutput(*out, data)
+}
+
+// errBreaking The check found breaking changes, the report has been printed
+var errBreaking = errors.New("breaking enum changes found")
+
+func runCheck(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	lockFile := fs.String("lock", "goenum.lock.json", "the lockfile created by goenum lock")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	_ = fs.Parse(args)
+	data, err := os.ReadFile(*lockFile)
+	if err != nil {
+		return err
+	}
+	old := &goenum.Lock{}
+	if err = json.Unmarshal(data, old); err != nil {
+		return fmt.Errorf("parse %s: %v", *lockFile, err)
+	}
+	if data, err = snapshot(fs.Args()); err != nil {
+		return err
+	}
+	current := &goenum.Lock{}
+	if err = json.Unmarshal(data, current); err != nil {
+		return err
+	}
+	report := goenum.CompareLock(old, current)
+	if *asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		_, err = io.WriteString(w, report.String())
+	}
+	if err != nil {
+		return err
+	}
+	if report.Breaking() {
+		return errBreaking
+	}
+	return nil
+}
+
+// snapshot Run the registrar program printing the Lock of the packages
+func snapshot(patterns []string) ([]byte, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no package specified")
+	}
+	pkgPaths, err := resolvePackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	program, err := lockProgram(pkgPaths)
+	if err != nil {
+		return nil, err
+	}
+	return runProgram(program)
+}
+
+func writeOutput(file string, data []byte) error {
+	if file == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
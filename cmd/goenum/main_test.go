@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func requireGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+	file := filepath.Join("testdata", name)
+	if *update {
+		require.Nil(t, os.WriteFile(file, data, 0644))
+	}
+	expected, err := os.ReadFile(file)
+	require.Nil(t, err)
+	require.Equal(t, string(expected), string(data))
+}
+
+func TestParseTypeRefs(t *testing.T) {
+	refs, imports, err := parseTypeRefs([]string{"example.com/a.A", "example.com/b.B", "example.com/a.C"})
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"example.com/a": "p0", "example.com/b": "p1"}, imports)
+	require.Equal(t, typeRef{Alias: "p0", PkgPath: "example.com/a", Name: "C"}, refs[2])
+	for _, name := range []string{"A", "example.com/a.", "example.com/a"} {
+		_, _, err = parseTypeRefs([]string{name})
+		require.NotNil(t, err, name)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	program, err := openAPIProgram([]string{"github.com/lvyahui8/goenum/internal.TradeOrder"})
+	require.Nil(t, err)
+	data, err := runProgram(program)
+	require.Nil(t, err)
+	requireGolden(t, "openapi.json", data)
+}
+
+func TestExport(t *testing.T) {
+	out := t.TempDir()
+	require.Nil(t, runExport([]string{"-lang", "java", "-java-package", "com.example", "-o", out,
+		"github.com/lvyahui8/goenum/internal/pkga"}))
+	data, err := os.ReadFile(filepath.Join(out, "com", "example", "github_com", "lvyahui8", "goenum", "internal", "pkga", "Status.java"))
+	require.Nil(t, err)
+	requireGolden(t, "Status.java", data)
+}
+
+func TestLockAndCheck(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "goenum.lock.json")
+	require.Nil(t, runLock([]string{"-o", lockFile, "github.com/lvyahui8/goenum/internal/pkga"}))
+	data, err := os.ReadFile(lockFile)
+	require.Nil(t, err)
+	requireGolden(t, "pkga.lock.json", data)
+
+	out := &bytes.Buffer{}
+	require.Nil(t, runCheck([]string{"-lock", lockFile, "github.com/lvyahui8/goenum/internal/pkga"}, out))
+	require.Equal(t, "no changes\n", out.String())
+
+	// 相对路径与包含main包的模式通过go list解析为导入路径
+	out.Reset()
+	require.Nil(t, runCheck([]string{"-lock", lockFile, "../../internal/pkga"}, out))
+	require.Equal(t, "no changes\n", out.String())
+	_, err = snapshot([]string{"../../internal/pkga/..."})
+	require.Nil(t, err)
+	_, err = snapshot([]string{"."})
+	require.EqualError(t, err, "no package matches .")
+
+	// 模拟旧版本中Pending之前还有一个已被删除的成员
+	out.Reset()
+	err = runCheck([]string{"-lock", "testdata/pkga.old.lock.json", "-json", "github.com/lvyahui8/goenum/internal/pkga"}, out)
+	require.Equal(t, errBreaking, err)
+	requireGolden(t, "pkga.check.json", out.Bytes())
+}
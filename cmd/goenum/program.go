@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/lvyahui8/goenum"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// typeRef A Go type referenced on the command line, such as github.com/lvyahui8/goenum/internal.ShipRequest
+type typeRef struct {
+	Alias   string
+	PkgPath string
+	Name    string
+}
+
+// parseTypeRefs Parse the qualified type names, packages are imported with the alias p0, p1...
+func parseTypeRefs(names []string) (refs []typeRef, imports map[string]string, err error) {
+	imports = make(map[string]string)
+	for _, name := range names {
+		i := strings.LastIndex(name, ".")
+		if i <= 0 || i == len(name)-1 || strings.LastIndex(name, "/") > i {
+			return nil, nil, fmt.Errorf("invalid type %q, expect import/path.TypeName", name)
+		}
+		pkgPath := name[:i]
+		alias, exist := imports[pkgPath]
+		if !exist {
+			alias = "p" + strconv.Itoa(len(imports))
+			imports[pkgPath] = alias
+		}
+		refs = append(refs, typeRef{Alias: alias, PkgPath: pkgPath, Name: name[i+1:]})
+	}
+	return
+}
+
+var openAPITemplate = template.Must(template.New("openapi").Parse(`package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/lvyahui8/goenum"
+{{- range $path, $alias := .Imports}}
+	{{$alias}} {{printf "%q" $path}}
+{{- end}}
+)
+
+func main() {
+	components := goenum.OpenAPIComponents(
+	{{- range .Types}}
+		{{.Alias}}.{{.Name}}{},
+	{{- end}}
+	)
+	data, err := json.MarshalIndent(map[string]any{"components": components}, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = os.Stdout.Write(append(data, '\n'))
+}
+`))
+
+// openAPIProgram Generate the registrar program printing the OpenAPI components of the struct types
+func openAPIProgram(names []string) ([]byte, error) {
+	refs, imports, err := parseTypeRefs(names)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	err = openAPITemplate.Execute(buf, map[string]any{"Imports": imports, "Types": refs})
+	return buf.Bytes(), err
+}
+
+var exportTemplate = template.Must(template.New("export").Parse(`package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/lvyahui8/goenum"
+{{- range .PkgPaths}}
+	_ {{printf "%q" .}}
+{{- end}}
+)
+
+func main() {
+	files, err := goenum.Export(goenum.ExportOptions{
+		Lang:        {{printf "%q" .Lang}},
+		JavaPackage: {{printf "%q" .JavaPackage}},
+		PkgPaths: []string{
+		{{- range .PkgPaths}}
+			{{printf "%q" .}},
+		{{- end}}
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	if err = json.NewEncoder(os.Stdout).Encode(files); err != nil {
+		panic(err)
+	}
+}
+`))
+
+// exportProgram Generate the registrar program importing the packages and printing the exported files as JSON
+func exportProgram(opts goenum.ExportOptions) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := exportTemplate.Execute(buf, opts)
+	return buf.Bytes(), err
+}
+
+var lockTemplate = template.Must(template.New("lock").Parse(`package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/lvyahui8/goenum"
+{{- range .}}
+	_ {{printf "%q" .}}
+{{- end}}
+)
+
+func main() {
+	data, err := json.MarshalIndent(goenum.Snapshot(
+	{{- range .}}
+		{{printf "%q" .}},
+	{{- end}}
+	), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	_, _ = os.Stdout.Write(append(data, '\n'))
+}
+`))
+
+// lockProgram Generate the registrar program printing the Lock of the enumeration types declared in the packages
+func lockProgram(pkgPaths []string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := lockTemplate.Execute(buf, pkgPaths)
+	return buf.Bytes(), err
+}
+
+// resolvePackages Resolve the package patterns to import paths with go list, such as ./internal/... or a full import path.
+// The generated program imports the packages by import path, so relative patterns must be resolved before.
+// Main packages can not be imported and are skipped
+func resolvePackages(patterns []string) ([]string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.Command("go", append([]string{"list", "-f", `{{if ne .Name "main"}}{{.ImportPath}}{{end}}`}, patterns...)...)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("resolve packages: %v\n%s", err, stderr.String())
+	}
+	pkgPaths := strings.Fields(stdout.String())
+	if len(pkgPaths) == 0 {
+		return nil, fmt.Errorf("no package matches %s", strings.Join(patterns, " "))
+	}
+	return pkgPaths, nil
+}
+
+// runProgram Run the generated program with go run inside the current module, and return its stdout
+func runProgram(program []byte) ([]byte, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(wd, "goenum-gen-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "main.go")
+	if err = os.WriteFile(file, program, 0644); err != nil {
+		return nil, err
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.Command("go", "run", file)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run registrar program: %v\n%s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,23 @@
+// Code generated by goenum export. DO NOT EDIT.
+
+package com.example.github_com.lvyahui8.goenum.internal.pkga;
+
+/**
+ * pkga.Status
+ */
+public enum Status {
+    Created("Created"),
+    Pending("Pending"),
+    Success("Success"),
+    Failed("Failed");
+
+    private final String goName;
+
+    Status(String goName) {
+        this.goName = goName;
+    }
+
+    public String getGoName() {
+        return goName;
+    }
+}
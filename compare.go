@@ -0,0 +1,191 @@
+package goenum
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeMismatchError Two enumeration instances of different types are compared
+type TypeMismatchError struct {
+	Left, Right string
+}
+
+func (e *TypeMismatchError) Error() string {
+	return "goenum: can not compare " + e.Left + " with " + e.Right
+}
+
+// CompareChecked Compare the ordinals of a and b like Compare, but return the *TypeMismatchError instead of panicking.
+// The zero value has no type and is comparable with any enumeration
+func CompareChecked(a, b EnumDefinition) (int, error) {
+	if err := checkComparable(a, b); err != nil {
+		return 0, err
+	}
+	return a.Ordinal() - b.Ordinal(), nil
+}
+
+func checkComparable(a, b EnumDefinition) error {
+	if a.Type() != b.Type() && a.Type() != "" && b.Type() != "" {
+		return &TypeMismatchError{Left: a.Type(), Right: b.Type()}
+	}
+	return nil
+}
+
+// mustComparable Panic with a *TypeMismatchError if the types of a and b differ
+func mustComparable(a, b EnumDefinition) {
+	if err := checkComparable(a, b); err != nil {
+		panic(err)
+	}
+}
+
+// CompareOrdinal A comparator by ordinal, such as slices.SortFunc(states, goenum.CompareOrdinal[TradeState]).
+// It panics with a *TypeMismatchError if the types of a and b differ, which can only happen when T is an interface
+func CompareOrdinal[T EnumDefinition](a, b T) int {
+	mustComparable(a, b)
+	return a.Ordinal() - b.Ordinal()
+}
+
+// CompareName A comparator by name, it panics like CompareOrdinal
+func CompareName[T EnumDefinition](a, b T) int {
+	mustComparable(a, b)
+	return strings.Compare(a.Name(), b.Name())
+}
+
+// ByAttribute Create a comparator by the attribute name, such as a priority declared by a method Priority() int,
+// see Attributes. Instances with equal attribute values are sorted by ordinal.
+// It panics if T has no such attribute, and the comparator panics like CompareOrdinal
+func ByAttribute[T EnumDefinition](name string) func(a, b T) int {
+	values := ValuesView[T]()
+	if len(values) > 0 {
+		if _, ok := attributeOf(values[0], name); !ok {
+			panic("goenum: " + values[0].Type() + " has no attribute " + name)
+		}
+	}
+	// 预先读取已注册成员的属性，避免每次比较都反射调用
+	attrs := make([]any, len(values))
+	for i, v := range values {
+		attrs[i], _ = attributeOf(v, name)
+	}
+	valueOf := func(e T) any {
+		if i := e.Ordinal(); i >= 0 && i < len(values) && values[i].Equals(e) {
+			return attrs[i]
+		}
+		v, _ := attributeOf(e, name)
+		return v
+	}
+	return func(a, b T) int {
+		mustComparable(a, b)
+		if c := compareAttribute(valueOf(a), valueOf(b)); c != 0 {
+			return c
+		}
+		return a.Ordinal() - b.Ordinal()
+	}
+}
+
+// ByOrder Create a comparator by an explicit order, such as ByOrder(TradePaid, TradeCreated).
+// Instances not listed come after the listed ones, sorted by ordinal. The comparator panics like CompareOrdinal
+func ByOrder[T EnumDefinition](order ...T) func(a, b T) int {
+	position := make(map[string]int, len(order))
+	for i, e := range order {
+		if _, exist := position[e.Name()]; !exist {
+			position[e.Name()] = i
+		}
+	}
+	positionOf := func(e T) int {
+		if i, ok := position[e.Name()]; ok {
+			return i
+		}
+		return len(order)
+	}
+	return func(a, b T) int {
+		mustComparable(a, b)
+		if c := positionOf(a) - positionOf(b); c != 0 {
+			return c
+		}
+		return a.Ordinal() - b.Ordinal()
+	}
+}
+
+// Min The instance with the smallest ordinal
+func Min[T EnumDefinition](first T, rest ...T) T {
+	res := first
+	for _, e := range rest {
+		if CompareOrdinal(e, res) < 0 {
+			res = e
+		}
+	}
+	return res
+}
+
+// Max The instance with the largest ordinal
+func Max[T EnumDefinition](first T, rest ...T) T {
+	res := first
+	for _, e := range rest {
+		if CompareOrdinal(e, res) > 0 {
+			res = e
+		}
+	}
+	return res
+}
+
+// Clamp Limit e to the ordinal range from lo to hi, both inclusive, such as Clamp(state, TradePaid, TradeShipped)
+func Clamp[T EnumDefinition](e, lo, hi T) T {
+	if CompareOrdinal(e, lo) < 0 {
+		return lo
+	}
+	if CompareOrdinal(e, hi) > 0 {
+		return hi
+	}
+	return e
+}
+
+func attributeOf(e EnumDefinition, name string) (any, bool) {
+	for _, attr := range Attributes(e) {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+	return nil, false
+}
+
+// compareAttribute Compare the values returned by attributeValue, false is less than true
+func compareAttribute(a, b any) int {
+	switch x := a.(type) {
+	case int64:
+		if y, ok := b.(int64); ok {
+			return compareOrdered(x, y)
+		}
+	case uint64:
+		if y, ok := b.(uint64); ok {
+			return compareOrdered(x, y)
+		}
+	case float64:
+		if y, ok := b.(float64); ok {
+			return compareOrdered(x, y)
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0
+			}
+			if x {
+				return 1
+			}
+			return -1
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func compareOrdered[V int64 | uint64 | float64](x, y V) int {
+	if x < y {
+		return -1
+	}
+	if x > y {
+		return 1
+	}
+	return 0
+}
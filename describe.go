@@ -0,0 +1,83 @@
+package goenum
+
+import "reflect"
+
+// TypeDescriptor Description of a registered enumeration type
+type TypeDescriptor struct {
+	// Name The qualified type name, such as internal.TradeState or *internal.ColorEnum
+	Name string `json:"name"`
+	// PkgPath The import path of the package declaring the type
+	PkgPath string       `json:"pkgPath"`
+	Type    reflect.Type `json:"-"`
+	// Pointer Whether the members are pointers, such as *ColorEnum
+	Pointer bool               `json:"pointer"`
+	Size    int                `json:"size"`
+	Members []MemberDescriptor `json:"members"`
+}
+
+// MemberDescriptor Description of an enumeration instance
+type MemberDescriptor struct {
+	Ordinal int    `json:"ordinal"`
+	Name    string `json:"name"`
+	// Deprecated The member has been removed from its dynamic definition, see IsDeprecated
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Attributes See Attributes
+	Attributes []Attribute    `json:"attributes,omitempty"`
+	Value      EnumDefinition `json:"-"`
+}
+
+// Types Describe all enumeration types of the default registry, sorted by name
+func Types() []TypeDescriptor {
+	return defaultRegistry.Types()
+}
+
+// Describe Describe the enumeration type T of the default registry, return false if T has no members
+func Describe[T EnumDefinition]() (TypeDescriptor, bool) {
+	return DescribeIn[T](defaultRegistry)
+}
+
+// DescribeIn Describe the enumeration type T of the registry reg, including the members of its ancestors
+func DescribeIn[T EnumDefinition](reg *Registry) (TypeDescriptor, bool) {
+	return reg.describe(typeKey(reflect.TypeOf((*T)(nil)).Elem()))
+}
+
+// Types Describe all enumeration types of the registry and its ancestors, sorted by name
+func (reg *Registry) Types() []TypeDescriptor {
+	keys := make(map[string]bool)
+	for r := reg; r != nil; r = r.parent {
+		for k := range r.load().type2enums {
+			keys[k] = true
+		}
+	}
+	res := make([]TypeDescriptor, 0, len(keys))
+	for _, k := range sortedKeys(keys) {
+		if d, ok := reg.describe(k); ok {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func (reg *Registry) describe(key string) (TypeDescriptor, bool) {
+	enums := reg.values(key)
+	if len(enums) == 0 {
+		return TypeDescriptor{}, false
+	}
+	t := reflect.TypeOf(enums[0])
+	d := TypeDescriptor{Name: key, Type: t, Pointer: t.Kind() == reflect.Ptr, Size: len(enums)}
+	if d.Pointer {
+		d.PkgPath = t.Elem().PkgPath()
+	} else {
+		d.PkgPath = t.PkgPath()
+	}
+	for _, e := range enums {
+		d.Members = append(d.Members, MemberDescriptor{
+			Ordinal:    e.Ordinal(),
+			Name:       e.Name(),
+			Deprecated: reg.IsDeprecated(e),
+			Attributes: Attributes(e),
+			Value:      e,
+		})
+	}
+	return d, true
+}
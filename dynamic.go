@@ -0,0 +1,569 @@
+package goenum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AttrKind Kind of a dynamic enumeration attribute
+type AttrKind string
+
+const (
+	AttrString  AttrKind = "string"
+	AttrInt     AttrKind = "int"
+	AttrFloat   AttrKind = "float"
+	AttrBool    AttrKind = "bool"
+	AttrStrings AttrKind = "strings"
+)
+
+// AttrSpec Declaration of a dynamic enumeration attribute
+type AttrSpec struct {
+	Kind AttrKind
+	// Required The attribute must be present in the definition
+	Required bool
+}
+
+// AttrSchema Attributes allowed in the definitions of a dynamic enumeration type, keyed by attribute name
+type AttrSchema map[string]AttrSpec
+
+// Attrs Attributes of a dynamic enumeration member, validated against the AttrSchema.
+// Values are normalized to string, int64, float64, bool and []string
+type Attrs map[string]any
+
+// String Get the string attribute, return "" if absent
+func (a Attrs) String(name string) string {
+	v, _ := a[name].(string)
+	return v
+}
+
+// Int Get the int attribute, return 0 if absent
+func (a Attrs) Int(name string) int64 {
+	v, _ := a[name].(int64)
+	return v
+}
+
+// Float Get the float attribute, return 0 if absent
+func (a Attrs) Float(name string) float64 {
+	v, _ := a[name].(float64)
+	return v
+}
+
+// Bool Get the bool attribute, return false if absent
+func (a Attrs) Bool(name string) bool {
+	v, _ := a[name].(bool)
+	return v
+}
+
+// Strings Get the strings attribute, return nil if absent
+func (a Attrs) Strings(name string) []string {
+	v, _ := a[name].([]string)
+	return v
+}
+
+// DefinitionError The definition of dynamic enumerations is invalid, all problems are listed
+type DefinitionError struct {
+	Problems []string
+}
+
+func (e *DefinitionError) Error() string {
+	return "goenum: invalid enum definition: " + strings.Join(e.Problems, "; ")
+}
+
+// Definition The data loaded by DynamicType, in JSON or YAML:
+//
+//	type: internal.BizCode
+//	members:
+//	  - name: Refund
+//	    ordinal: 3
+//	    attributes:
+//	      code: 2
+//	      desc: 退款服务
+type Definition struct {
+	// Type The enumeration type, optional, checked against the type of DynamicType if present
+	Type    string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Members []MemberDefinition `json:"members" yaml:"members"`
+}
+
+// MemberDefinition The definition of a dynamic enumeration member
+type MemberDefinition struct {
+	Name string `json:"name" yaml:"name"`
+	// Ordinal The expected ordinal, optional. It must be the next free ordinal of the type,
+	// so that a member added to the Go code later can not silently shift the ordinals of the dynamic members
+	Ordinal    *int           `json:"ordinal,omitempty" yaml:"ordinal,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+// DynamicType Register members of the enumeration type T from data sources at runtime. Dynamic members coexist with
+// the members declared by NewEnum, and take the ordinals following them.
+type DynamicType[T EnumDefinition] struct {
+	reg    *Registry
+	schema AttrSchema
+	build  func(name string, attrs Attrs) (T, error)
+	// members Attributes of the members registered by this DynamicType, including the deprecated ones
+	members map[string]Attrs
+}
+
+// dynamicMu Serialize the loading of dynamic definitions. Change events are delivered after releasing it,
+// in the order of the tickets taken while holding it, so a slow subscriber only blocks the reloads of its own type
+var dynamicMu sync.Mutex
+
+// NewDynamicType Declare the dynamic enumeration type T. The attributes of the members are validated against schema,
+// and build creates the instance of T (the Enum field is set afterwards, like the src of NewEnum).
+// If build is nil, attributes are assigned to the exported fields of T with the same name (case-insensitive),
+// a field of another kind, or too small for the value, such as a string field for an int attribute, is a definition error.
+func NewDynamicType[T EnumDefinition](schema AttrSchema, build func(name string, attrs Attrs) (T, error)) *DynamicType[T] {
+	return NewDynamicTypeIn[T](defaultRegistry, schema, build)
+}
+
+// NewDynamicTypeIn Declare the dynamic enumeration type T whose members are registered to the registry reg,
+// such as the registry of a tenant, see NewDynamicType
+func NewDynamicTypeIn[T EnumDefinition](reg *Registry, schema AttrSchema, build func(name string, attrs Attrs) (T, error)) *DynamicType[T] {
+	if build == nil {
+		build = buildByFields[T]
+	}
+	return &DynamicType[T]{reg: reg, schema: schema, build: build, members: make(map[string]Attrs)}
+}
+
+// LoadFile Load the definition file, YAML if the extension is .yaml or .yml, otherwise JSON
+func (d *DynamicType[T]) LoadFile(path string) ([]T, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return d.load(data, isYAML(path))
+}
+
+// LoadFS Load the definition file in fsys, such as an embed.FS
+func (d *DynamicType[T]) LoadFS(fsys fs.FS, path string) ([]T, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return d.load(data, isYAML(path))
+}
+
+// LoadJSON Load the JSON definition
+func (d *DynamicType[T]) LoadJSON(r io.Reader) ([]T, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.load(data, false)
+}
+
+// LoadYAML Load the YAML definition
+func (d *DynamicType[T]) LoadYAML(r io.Reader) ([]T, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.load(data, true)
+}
+
+// ReloadFile Reload the definition file as the complete set of the members of d. Members loaded before by d are
+// updated in place, members absent from the file are deprecated (see IsDeprecated), new members are registered.
+// The changes are published atomically and delivered to the subscribers of Watch; nothing changes if the file is invalid
+func (d *DynamicType[T]) ReloadFile(path string) (ChangeEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+	return d.reload(data, isYAML(path))
+}
+
+// ReloadJSON Reload the JSON definition, see ReloadFile
+func (d *DynamicType[T]) ReloadJSON(r io.Reader) (ChangeEvent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+	return d.reload(data, false)
+}
+
+// ReloadYAML Reload the YAML definition, see ReloadFile
+func (d *DynamicType[T]) ReloadYAML(r io.Reader) (ChangeEvent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+	return d.reload(data, true)
+}
+
+// WatchFile Reload the definition file immediately, and then whenever its modification time or size changes,
+// checked every interval until ctx is done. Errors of reloading are passed to onError (nil to ignore), and the
+// registered members stay unchanged on error.
+func (d *DynamicType[T]) WatchFile(ctx context.Context, path string, interval time.Duration, onError func(error)) {
+	var modTime time.Time
+	size := int64(-1)
+	check := func() {
+		info, err := os.Stat(path)
+		if err == nil && info.ModTime().Equal(modTime) && info.Size() == size {
+			return
+		}
+		if err == nil {
+			modTime, size = info.ModTime(), info.Size()
+			_, err = d.ReloadFile(path)
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func (d *DynamicType[T]) reload(data []byte, isYAML bool) (ChangeEvent, error) {
+	def, err := parseDefinition(data, isYAML)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+	return d.apply(def, true)
+}
+
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func parseDefinition(data []byte, isYAML bool) (*Definition, error) {
+	def := &Definition{}
+	if isYAML {
+		return def, yaml.Unmarshal(data, def)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return def, decoder.Decode(def)
+}
+
+// load Register the members of the definition, nothing is registered if the definition is invalid
+func (d *DynamicType[T]) load(data []byte, isYAML bool) ([]T, error) {
+	def, err := parseDefinition(data, isYAML)
+	if err != nil {
+		return nil, err
+	}
+	event, err := d.apply(def, false)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]T, 0, len(event.Added))
+	for _, e := range event.Added {
+		res = append(res, e.(T))
+	}
+	return res, nil
+}
+
+// apply Validate the whole definition against the current registry, and then publish all changes at once.
+// If reload is true, the definition is the complete set of the members of d: members defined before are updated,
+// and members absent from the definition are deprecated. Otherwise all members of the definition must be new.
+func (d *DynamicType[T]) apply(def *Definition, reload bool) (event ChangeEvent, err error) {
+	ticket, err := d.applyLocked(def, reload, &event)
+	if err == nil && !event.IsEmpty() {
+		d.reg.notify(event, ticket)
+	}
+	return
+}
+
+// applyLocked Publish the changes of the definition to event while holding dynamicMu,
+// and take the ticket of the event if anything changed
+func (d *DynamicType[T]) applyLocked(def *Definition, reload bool, event *ChangeEvent) (ticket uint64, err error) {
+	dynamicMu.Lock()
+	defer dynamicMu.Unlock()
+	tKey := typeKey(reflect.TypeOf((*T)(nil)).Elem())
+	event.Type = tKey
+	err = d.reg.update(func(r *registry) error {
+		var problems []string
+		if def.Type != "" && def.Type != tKey && def.Type != tKey[strings.LastIndex(tKey, ".")+1:] {
+			problems = append(problems, "type "+strconv.Quote(def.Type)+" mismatches "+tKey)
+		}
+		if extendedErr := d.reg.checkExtended(tKey); extendedErr != nil && hasNewMember(r, tKey, def) {
+			problems = append(problems, strings.TrimPrefix(extendedErr.Error(), "goenum: "))
+		}
+		nextOrdinal := r.nextOrdinal(tKey)
+		names := make(map[string]bool)
+		members := make([]T, len(def.Members))
+		attrsList := make([]Attrs, len(def.Members))
+		for i, m := range def.Members {
+			field := "members[" + strconv.Itoa(i) + "]"
+			existing := r.find(tKey, m.Name)
+			_, owned := d.members[m.Name]
+			switch {
+			case m.Name == "":
+				problems = append(problems, field+".name: required")
+			case names[m.Name] || (existing != nil && !(reload && owned)) || (existing == nil && r.contains(tKey, m.Name)):
+				problems = append(problems, field+".name: duplicate enum "+strconv.Quote(m.Name))
+			}
+			names[m.Name] = true
+			if existing != nil && reload && owned {
+				// 已有成员重新加载时保持原序号
+				if m.Ordinal != nil && *m.Ordinal != existing.Ordinal() {
+					problems = append(problems, fmt.Sprintf("%s.ordinal: %d mismatches the ordinal %d of %s",
+						field, *m.Ordinal, existing.Ordinal(), m.Name))
+				}
+			} else {
+				if m.Ordinal != nil && *m.Ordinal != nextOrdinal {
+					problems = append(problems, fmt.Sprintf("%s.ordinal: %d collides with the ordinals of %s, the next ordinal is %d",
+						field, *m.Ordinal, tKey, nextOrdinal))
+				}
+				nextOrdinal++
+			}
+			attrs, attrProblems := d.schema.validate(m.Attributes)
+			for _, p := range attrProblems {
+				problems = append(problems, field+".attributes."+p)
+			}
+			if len(attrProblems) > 0 {
+				continue
+			}
+			t, buildErr := d.build(m.Name, attrs)
+			if buildErr != nil {
+				problems = append(problems, field+": "+buildErr.Error())
+				continue
+			}
+			members[i], attrsList[i] = t, attrs
+		}
+		if len(problems) > 0 {
+			return &DefinitionError{Problems: problems}
+		}
+		for i, m := range def.Members {
+			existing := r.find(tKey, m.Name)
+			if existing == nil {
+				event.Added = append(event.Added, registerEnum(r, m.Name, members[i]))
+			} else if r.deprecated[tKey][m.Name] {
+				t := bindEnum(members[i], Enum{name: m.Name, _type: tKey, index: existing.Ordinal()})
+				r.replace(t)
+				r.setDeprecated(t, false)
+				event.Added = append(event.Added, t)
+			} else if changed := changedAttrs(d.members[m.Name], attrsList[i]); len(changed) > 0 {
+				t := bindEnum(members[i], Enum{name: m.Name, _type: tKey, index: existing.Ordinal()})
+				r.replace(t)
+				event.Changed = append(event.Changed, MemberChange{Old: existing, New: t, Attributes: changed})
+			}
+		}
+		if reload {
+			for _, name := range sortedKeys(d.members) {
+				if !names[name] && !r.deprecated[tKey][name] {
+					e := r.find(tKey, name)
+					r.setDeprecated(e, true)
+					event.Removed = append(event.Removed, e)
+				}
+			}
+		}
+		for i, m := range def.Members {
+			d.members[m.Name] = attrsList[i]
+		}
+		return nil
+	})
+	if err != nil || event.IsEmpty() {
+		return
+	}
+	return d.reg.ticket(tKey), nil
+}
+
+// hasNewMember Whether the definition registers a member not registered to r yet
+func hasNewMember(r *registry, tKey string, def *Definition) bool {
+	for _, m := range def.Members {
+		if r.find(tKey, m.Name) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// changedAttrs Names of the attributes with different values
+func changedAttrs(old, new Attrs) (names []string) {
+	for _, name := range sortedKeys(old) {
+		if v, ok := new[name]; !ok || !reflect.DeepEqual(old[name], v) {
+			names = append(names, name)
+		}
+	}
+	for _, name := range sortedKeys(new) {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+// validate Check the attributes against the schema and normalize their values
+func (s AttrSchema) validate(attributes map[string]any) (Attrs, []string) {
+	var problems []string
+	attrs := make(Attrs)
+	for _, name := range sortedKeys(attributes) {
+		spec, ok := s[name]
+		if !ok {
+			problems = append(problems, name+": undeclared attribute")
+			continue
+		}
+		v, ok := normalizeAttr(spec.Kind, attributes[name])
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: expect %s, got %v", name, spec.Kind, attributes[name]))
+			continue
+		}
+		attrs[name] = v
+	}
+	for _, name := range sortedKeys(s) {
+		if _, ok := attributes[name]; !ok && s[name].Required {
+			problems = append(problems, name+": required")
+		}
+	}
+	return attrs, problems
+}
+
+func normalizeAttr(kind AttrKind, v any) (any, bool) {
+	switch kind {
+	case AttrString:
+		s, ok := v.(string)
+		return s, ok
+	case AttrBool:
+		b, ok := v.(bool)
+		return b, ok
+	case AttrInt:
+		switch n := v.(type) {
+		case int:
+			return int64(n), true
+		case int64:
+			return n, true
+		case uint64:
+			return int64(n), n <= 1<<63-1
+		case json.Number:
+			i, err := n.Int64()
+			return i, err == nil
+		}
+	case AttrFloat:
+		switch n := v.(type) {
+		case int:
+			return float64(n), true
+		case float64:
+			return n, true
+		case json.Number:
+			f, err := n.Float64()
+			return f, err == nil
+		}
+	case AttrStrings:
+		list, ok := v.([]any)
+		if !ok {
+			return nil, false
+		}
+		res := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			res = append(res, s)
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// buildByFields Assign the attributes to the exported fields of T with the same name.
+// An attribute is only converted to a field of the same kind family without loss, such as int64 to int8 in range
+func buildByFields[T EnumDefinition](_ string, attrs Attrs) (T, error) {
+	var t T
+	v := reflect.ValueOf(&t).Elem()
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	for _, name := range sortedKeys(attrs) {
+		sf, ok := v.Type().FieldByNameFunc(func(fieldName string) bool {
+			return strings.EqualFold(fieldName, name)
+		})
+		if !ok || sf.PkgPath != "" {
+			return t, errors.New("no exported field for attribute " + strconv.Quote(name))
+		}
+		field := v.FieldByIndex(sf.Index)
+		if err := assignAttr(field, attrs[name]); err != nil {
+			return t, fmt.Errorf("attribute %s %v field %s of %s", strconv.Quote(name), err, sf.Name, field.Type())
+		}
+	}
+	return t, nil
+}
+
+// assignAttr Assign the normalized attribute value to field, the error completes "attribute x ... field F of type"
+func assignAttr(field reflect.Value, attr any) error {
+	value := reflect.ValueOf(attr)
+	if value.Type().AssignableTo(field.Type()) {
+		field.Set(value)
+		return nil
+	}
+	switch n := attr.(type) {
+	case int64:
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if field.OverflowInt(n) {
+				return fmt.Errorf("value %d overflows", n)
+			}
+			field.SetInt(n)
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n < 0 || field.OverflowUint(uint64(n)) {
+				return fmt.Errorf("value %d overflows", n)
+			}
+			field.SetUint(uint64(n))
+			return nil
+		}
+	case float64:
+		if field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64 {
+			if field.OverflowFloat(n) {
+				return fmt.Errorf("value %v overflows", n)
+			}
+			field.SetFloat(n)
+			return nil
+		}
+	case string:
+		if field.Kind() == reflect.String {
+			field.SetString(n)
+			return nil
+		}
+	case bool:
+		if field.Kind() == reflect.Bool {
+			field.SetBool(n)
+			return nil
+		}
+	case []string:
+		if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String {
+			list := reflect.MakeSlice(field.Type(), len(n), len(n))
+			for i, item := range n {
+				list.Index(i).SetString(item)
+			}
+			field.Set(list)
+			return nil
+		}
+	}
+	return errors.New("can not be assigned to")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
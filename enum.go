@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -21,7 +23,7 @@ type EnumDefinition interface {
 	Type() string
 	// Ordinal Get the ordinal of the enumeration, starting from zero and increasing in declared order.
 	Ordinal() int
-	// Compare -Compare with the ordinal value of another enumeration
+	// Compare -Compare with the ordinal value of another enumeration of the same type, panic if the types differ
 	Compare(other EnumDefinition) int
 }
 
@@ -54,7 +56,10 @@ func (e Enum) Type() string {
 	return e._type
 }
 
+// Compare Compare the ordinals, panic with a *TypeMismatchError if other is of another type.
+// Use CompareChecked where the types may differ
 func (e Enum) Compare(other EnumDefinition) int {
+	mustComparable(e, other)
 	return e.Ordinal() - other.Ordinal()
 }
 
@@ -66,30 +71,46 @@ func (e Enum) MarshalText() (text []byte, err error) {
 	return []byte(e.Name()), nil
 }
 
-// name2enumsMap Name to enumeration instance mapping.
-// Value uses slice to store enumeration instances with different types but conflicting names
-var name2enumsMap = make(map[string][]EnumDefinition)
-
-// type2enumsMap The mapping from enumeration type to all enumerations,
-// where key is the string representation of the enumeration type
-var type2enumsMap = make(map[string][]EnumDefinition)
-
-// typeIndexMap Store instance counters of different enumeration types for calculating Ordinal.
-var typeIndexMap = make(map[string]int)
-
 // NewEnum Create a new enumeration. If an enumeration instance with the same Type and Name already exists,
-// the current method will throw a panic to prevent duplicate enumeration creation
+// the current method will throw a panic to prevent duplicate enumeration creation.
 func NewEnum[T EnumDefinition](name string, src ...T) T {
-	if IsValidEnum[T](name) {
-		panic("Enum must be unique")
-	}
+	return NewEnumIn[T](defaultRegistry, name, src...)
+}
+
+// NewEnumIn Create a new enumeration in the registry reg, see NewEnum.
+// The enumeration must also be unique among the members of the ancestors of reg
+func NewEnumIn[T EnumDefinition](reg *Registry, name string, src ...T) T {
 	var t T
 	if len(src) > 0 {
 		t = src[0]
 	}
-	v := reflect.ValueOf(t)
-	tFullName := typeKey(v.Type())
+	reg.append(func(r *registry) {
+		tKey := typeKey(reflect.TypeOf((*T)(nil)).Elem())
+		if r.contains(tKey, name) {
+			panic("Enum must be unique")
+		}
+		if err := reg.checkExtended(tKey); err != nil {
+			panic(err.Error())
+		}
+		t = registerEnum(r, name, t)
+	})
+	return t
+}
+
+// registerEnum Bind the Enum field of t to a new Enum with the next ordinal of its type, and register it to r
+func registerEnum[T EnumDefinition](r *registry, name string, t T) T {
+	tFullName := typeKey(reflect.TypeOf((*T)(nil)).Elem())
+	idx := r.extend(tFullName)
+	t = bindEnum(t, Enum{name: name, _type: tFullName, index: idx})
+	r.typeIndex[tFullName] = idx + 1
+	r.type2enums[tFullName] = append(r.type2enums[tFullName], t)
+	r.name2enums[name] = append(r.name2enums[name], t)
+	return t
+}
 
+// bindEnum Set the Enum field of t
+func bindEnum[T EnumDefinition](t T, e Enum) T {
+	v := reflect.ValueOf(t)
 	isPtr := v.Kind() == reflect.Ptr
 	if isPtr {
 		if v.IsNil() {
@@ -100,46 +121,61 @@ func NewEnum[T EnumDefinition](name string, src ...T) T {
 	}
 	elem := reflect.Indirect(v)
 	enumFiled := elem.FieldByName(reflect.TypeOf(Enum{}).Name())
-
-	idx := typeIndexMap[tFullName]
-	e := Enum{name: name, _type: tFullName, index: idx}
 	if enumFiled.Kind() == reflect.Ptr {
 		enumFiled.Set(reflect.ValueOf(&e))
 	} else {
 		enumFiled.Set(reflect.ValueOf(e))
 	}
-	typeIndexMap[tFullName] = idx + 1
 	if isPtr {
-		t = v.Interface().(T)
-	} else {
-		t = reflect.Indirect(v).Interface().(T)
+		return v.Interface().(T)
 	}
-	type2enumsMap[tFullName] = append(type2enumsMap[tFullName], t)
-	name2enumsMap[name] = append(name2enumsMap[name], t)
-	return t
+	return reflect.Indirect(v).Interface().(T)
 }
 
 // ValueOf Find an enumeration instance based on the string, and return a zero value if not found
 func ValueOf[T EnumDefinition](name string) (t T, valid bool) {
-	enums := name2enumsMap[name]
-	for _, e := range enums {
-		if v, ok := e.(T); ok {
-			return v, true
+	return ValueOfIn[T](defaultRegistry, name)
+}
+
+// MustValueOf Like ValueOf, but panic if not found
+func MustValueOf[T EnumDefinition](name string) T {
+	t, valid := ValueOf[T](name)
+	if !valid {
+		panic("goenum: " + strconv.Quote(name) + " is not a member of " + typeKey(reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return t
+}
+
+// ValueOfIn Find an enumeration instance in the registry reg and its ancestors, see ValueOf
+func ValueOfIn[T EnumDefinition](reg *Registry, name string) (t T, valid bool) {
+	cache := cacheOf[T](reg)
+	if !cache.isInterface {
+		t, valid = cache.byName[name]
+		return
+	}
+	for ; reg != nil; reg = reg.parent {
+		for _, e := range reg.load().name2enums[name] {
+			if v, ok := e.(T); ok {
+				return v, true
+			}
 		}
 	}
 	return
 }
 
 // ValueOfIgnoreCase Ignoring case to obtain enumeration instances.
-// Note: This method involves one reflection call,
-// and its performance is slightly worse than the ValueOf method
+// Names are looked up in a case-folded index, which is built once per type and rebuilt after registering new members
 func ValueOfIgnoreCase[T EnumDefinition](name string) (t T, valid bool) {
-	values := Values[T]()
-	for _, e := range values {
-		if strings.EqualFold(e.Name(), name) {
-			return e, true
-		}
+	return ValueOfIgnoreCaseIn[T](defaultRegistry, name)
+}
+
+// ValueOfIgnoreCaseIn Ignoring case to obtain enumeration instances in the registry reg, see ValueOfIgnoreCase
+func ValueOfIgnoreCaseIn[T EnumDefinition](reg *Registry, name string) (t T, valid bool) {
+	cache := cacheOf[T](reg)
+	if t, valid = cache.byName[name]; valid {
+		return
 	}
+	t, valid = cache.byFoldedName[foldKey(name)]
 	return
 }
 
@@ -160,28 +196,37 @@ func Unmarshal[T EnumDefinition](data []byte) (t T, err error) {
 
 // Values Return all enumeration instances. The returned slice are sorted by ordinal
 func Values[T EnumDefinition]() []T {
-	var t T
-	var res []T
-	tName := typeKey(reflect.TypeOf(t))
-	for _, e := range type2enumsMap[tName] {
-		if v, ok := e.(T); ok {
-			res = append(res, v)
-		}
+	return ValuesIn[T](defaultRegistry)
+}
+
+// ValuesIn Return all enumeration instances in the registry reg and its ancestors, sorted by ordinal
+func ValuesIn[T EnumDefinition](reg *Registry) []T {
+	values := cacheOf[T](reg).values
+	if len(values) == 0 {
+		return nil
 	}
-	return res
+	return append(make([]T, 0, len(values)), values...)
 }
 
 // Size Number of instances of specified enumeration type
 func Size[T EnumDefinition]() int {
-	var t T
-	tName := typeKey(reflect.TypeOf(t))
-	return len(type2enumsMap[tName])
+	return SizeIn[T](defaultRegistry)
+}
+
+// SizeIn Number of instances of specified enumeration type in the registry reg and its ancestors
+func SizeIn[T EnumDefinition](reg *Registry) int {
+	return len(cacheOf[T](reg).values)
 }
 
 // GetEnumMap Get all enumeration instances of the specified type.
 // The key is the Name of the enumeration instance, and the value is the enumeration instance.
 func GetEnumMap[T EnumDefinition]() map[string]T {
-	values := Values[T]()
+	return GetEnumMapIn[T](defaultRegistry)
+}
+
+// GetEnumMapIn Get all enumeration instances of the specified type in the registry reg, see GetEnumMap
+func GetEnumMapIn[T EnumDefinition](reg *Registry) map[string]T {
+	values := ValuesIn[T](reg)
 	res := make(map[string]T)
 	for _, e := range values {
 		res[e.Name()] = e
@@ -220,6 +265,163 @@ func IsValidEnum[T EnumDefinition](name string) (valid bool) {
 	return
 }
 
+// IsValidEnumIn Determine if the incoming string is a valid enumeration in the registry reg
+func IsValidEnumIn[T EnumDefinition](reg *Registry, name string) (valid bool) {
+	_, valid = ValueOfIn[T](reg, name)
+	return
+}
+
 func typeKey(t reflect.Type) string {
 	return t.String()
 }
+
+// isEnumType Whether t is a registered enumeration type
+func isEnumType(t reflect.Type) bool {
+	return len(loadRegistry().type2enums[typeKey(t)]) > 0
+}
+
+// valueOfType Non-generic version of ValueOf, used when the enumeration type is only known by reflection.
+// The name is matched exactly first, and then case-insensitively
+func valueOfType(t reflect.Type, name string) (EnumDefinition, bool) {
+	enums := loadRegistry().type2enums[typeKey(t)]
+	for _, e := range enums {
+		if e.Name() == name {
+			return e, true
+		}
+	}
+	for _, e := range enums {
+		if strings.EqualFold(e.Name(), name) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// namesOfType Non-generic version of EnumNames
+func namesOfType(t reflect.Type) (names []string) {
+	for _, e := range loadRegistry().type2enums[typeKey(t)] {
+		names = append(names, e.Name())
+	}
+	return
+}
+
+// typeKeyOfName Resolve the type key of a registered enumeration type by its qualified name such as "internal.TradeState",
+// or its unqualified name such as "TradeState" if it is not ambiguous
+func typeKeyOfName(name string) (key string, ok bool) {
+	type2enums := loadRegistry().type2enums
+	if _, exist := type2enums[name]; exist {
+		return name, true
+	}
+	for k := range type2enums {
+		if k[strings.LastIndex(k, ".")+1:] != name {
+			continue
+		}
+		if ok {
+			return "", false
+		}
+		key, ok = k, true
+	}
+	return
+}
+
+// Attribute A named value of an enumeration instance
+type Attribute struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// definitionMethods Methods of EnumDefinition, they are not attributes
+var definitionMethods = func() map[string]bool {
+	res := make(map[string]bool)
+	t := reflect.TypeOf((*EnumDefinition)(nil)).Elem()
+	for i := 0; i < t.NumMethod(); i++ {
+		res[t.Method(i).Name] = true
+	}
+	// fmt.GoStringer
+	res["GoString"] = true
+	return res
+}()
+
+var enumDefinitionType = reflect.TypeOf((*EnumDefinition)(nil)).Elem()
+
+// Attributes Return the attributes of the enumeration instance, sorted by name.
+// Attributes are the results of the exported methods without parameters returning a single basic value,
+// enumeration, or slice of them, such as Code() int and IsFinal() bool. Enumerations are represented by their names.
+// The attribute name is the method name in lower camel case without the Get prefix, such as code and isFinal
+func Attributes(e EnumDefinition) (attrs []Attribute) {
+	v := reflect.ValueOf(e)
+	for i := 0; i < v.NumMethod(); i++ {
+		method := v.Type().Method(i)
+		mt := method.Type
+		// Type().Method 的函数类型包含receiver
+		if definitionMethods[method.Name] || mt.NumIn() != 1 || mt.NumOut() != 1 || !isAttributeType(mt.Out(0)) {
+			continue
+		}
+		attrs = append(attrs, Attribute{Name: attributeName(method.Name), Value: attributeValue(v.Method(i).Call(nil)[0])})
+	}
+	return
+}
+
+func isAttributeType(t reflect.Type) bool {
+	if t.Implements(enumDefinitionType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && isAttributeType(t.Elem())
+	}
+	return false
+}
+
+// attributeValue Convert to string, bool, int64, uint64, float64 or []any
+func attributeValue(v reflect.Value) any {
+	if v.Type().Implements(enumDefinitionType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return ""
+		}
+		return v.Interface().(EnumDefinition).Name()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	res := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		res[i] = attributeValue(v.Index(i))
+	}
+	return res
+}
+
+func attributeName(methodName string) string {
+	if len(methodName) > 3 && strings.HasPrefix(methodName, "Get") && strings.ToUpper(methodName[3:4]) == methodName[3:4] {
+		methodName = methodName[3:]
+	}
+	return strings.ToLower(methodName[:1]) + methodName[1:]
+}
+
+// typeKeys All registered enumeration type keys, sorted
+func typeKeys() []string {
+	type2enums := loadRegistry().type2enums
+	keys := make([]string, 0, len(type2enums))
+	for k := range type2enums {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typeOfKey The reflect.Type of the registered enumeration type key
+func typeOfKey(key string) reflect.Type {
+	return reflect.TypeOf(loadRegistry().type2enums[key][0])
+}
@@ -0,0 +1,157 @@
+package goenum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EnumMap A map with enumeration keys, stored as an array indexed by ordinal like java.util.EnumMap.
+// Iteration follows the ordinal order of the keys. Not safe for concurrent use.
+type EnumMap[E EnumDefinition, V any] struct {
+	// reg The registry the keys are members of, nil means the default registry
+	reg *Registry
+	// cache The index of the members of E, used to check the keys
+	cache   *typeCache[E]
+	values  []V
+	present []bool
+	len     int
+}
+
+// NewEnumMap Create an empty EnumMap
+func NewEnumMap[E EnumDefinition, V any]() *EnumMap[E, V] {
+	return NewEnumMapIn[E, V](defaultRegistry)
+}
+
+// NewEnumMapIn Create an empty EnumMap keyed by the members of E in the registry reg and its ancestors, see NewEnumMap
+func NewEnumMapIn[E EnumDefinition, V any](reg *Registry) *EnumMap[E, V] {
+	size := SizeIn[E](reg)
+	return &EnumMap[E, V]{reg: reg, values: make([]V, size), present: make([]bool, size)}
+}
+
+// registry The registry the keys are members of
+func (m *EnumMap[E, V]) registry() *Registry {
+	if m == nil || m.reg == nil {
+		return defaultRegistry
+	}
+	return m.reg
+}
+
+// member Return the ordinal of e, or panic if e is not a registered member
+func (m *EnumMap[E, V]) member(e E) int {
+	if m.cache != nil {
+		if ordinal, ok := m.cache.ordinalOf(e); ok {
+			return ordinal
+		}
+	}
+	// 成员可能在索引获取之后注册
+	m.cache = cacheOf[E](m.registry())
+	return m.cache.mustOrdinalOf(e)
+}
+
+// lookup Return the ordinal of e, or false if e is not a registered member, such as the zero value.
+// Unlike member, the refreshed index is not kept, so reading a map does not modify it
+func (m *EnumMap[E, V]) lookup(e E) (int, bool) {
+	if m.cache != nil {
+		if ordinal, ok := m.cache.ordinalOf(e); ok {
+			return ordinal, true
+		}
+	}
+	return cacheOf[E](m.registry()).ordinalOf(e)
+}
+
+// Put Associate v with e, return the previous value and whether it existed.
+// Panic if e is not a registered member, such as the zero value or a member of another registry
+func (m *EnumMap[E, V]) Put(e E, v V) (old V, existed bool) {
+	i := m.member(e)
+	m.grow(i + 1)
+	old, existed = m.values[i], m.present[i]
+	m.values[i], m.present[i] = v, true
+	if !existed {
+		m.len++
+	}
+	return
+}
+
+// Get The value associated with e
+func (m *EnumMap[E, V]) Get(e E) (v V, ok bool) {
+	i, member := m.lookup(e)
+	if !member || i >= len(m.present) || !m.present[i] {
+		return
+	}
+	return m.values[i], true
+}
+
+// Contains Whether e has an associated value
+func (m *EnumMap[E, V]) Contains(e E) bool {
+	_, ok := m.Get(e)
+	return ok
+}
+
+// Remove Delete the value associated with e, return false if not found
+func (m *EnumMap[E, V]) Remove(e E) bool {
+	i, member := m.lookup(e)
+	if !member || i >= len(m.present) || !m.present[i] {
+		return false
+	}
+	var zero V
+	m.values[i], m.present[i] = zero, false
+	m.len--
+	return true
+}
+
+// Len Number of the keys
+func (m *EnumMap[E, V]) Len() int {
+	return m.len
+}
+
+// Keys The keys sorted by ordinal
+func (m *EnumMap[E, V]) Keys() []E {
+	var keys []E
+	m.Each(func(e E, _ V) bool {
+		keys = append(keys, e)
+		return true
+	})
+	return keys
+}
+
+// Each Iterate the entries in ordinal order of the keys, abort if f returns false
+func (m *EnumMap[E, V]) Each(f func(e E, v V) bool) {
+	values := ValuesViewIn[E](m.registry())
+	for i, ok := range m.present {
+		if ok && i < len(values) && !f(values[i], m.values[i]) {
+			return
+		}
+	}
+}
+
+func (m *EnumMap[E, V]) String() string {
+	var entries []string
+	m.Each(func(e E, v V) bool {
+		entries = append(entries, e.Name()+":"+fmt.Sprint(v))
+		return true
+	})
+	return "{" + strings.Join(entries, ",") + "}"
+}
+
+// MarshalJSON Marshal to a JSON object keyed by the enumeration names
+func (m *EnumMap[E, V]) MarshalJSON() ([]byte, error) {
+	res := make(map[string]V, m.len)
+	m.Each(func(e E, v V) bool {
+		res[e.Name()] = v
+		return true
+	})
+	return json.Marshal(res)
+}
+
+// grow Extend the storage for the members registered after the map is created
+func (m *EnumMap[E, V]) grow(size int) {
+	if size <= len(m.present) {
+		return
+	}
+	values := make([]V, size)
+	copy(values, m.values)
+	present := make([]bool, size)
+	copy(present, m.present)
+	m.values, m.present = values, present
+}
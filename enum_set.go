@@ -3,6 +3,8 @@ package goenum
 import (
 	"encoding/json"
 	"fmt"
+	"math/bits"
+	"reflect"
 	"strings"
 )
 
@@ -18,11 +20,12 @@ type EnumSet[E EnumDefinition] interface {
 	// return the actual number added (excluding those that already exist)
 	AddRange(begin, end E) int
 	// Remove Delete element. If the deletion is successful, return true.
-	// If the element does not exist, return false
+	// If the element does not exist or is not a registered member, return false
 	Remove(e E) bool
 	// RemoveRange  According to the ordinal of the enumeration,
 	// continuously delete a segment of the enumeration and
-	// return the actual number of deletions  (excluding those that non-exist)
+	// return the actual number of deletions  (excluding those that non-exist).
+	// Nothing is deleted if begin or end is not a registered member
 	RemoveRange(begin, end E) int
 	// IsEmpty Is Set empty
 	IsEmpty() bool
@@ -31,7 +34,7 @@ type EnumSet[E EnumDefinition] interface {
 	// Len The current number of enumeration instances within the Set
 	Len() int
 	// Contains Does it contain the specified enumeration?
-	// Returns false if there is only one that does not exist in the Set
+	// Returns false if there is only one that does not exist in the Set or is not a registered member
 	Contains(enums ...E) bool
 	// ContainsAll  Determine if it contains another enumSet (subset relationship)
 	ContainsAll(set EnumSet[E]) bool
@@ -45,15 +48,27 @@ type EnumSet[E EnumDefinition] interface {
 	Clone() EnumSet[E]
 }
 
+// NewUnsafeEnumSet Create an empty set sized for the current members of E. The set grows on demand,
+// so members registered later, such as dynamic members, can be added too
 func NewUnsafeEnumSet[E EnumDefinition]() *UnsafeEnumSet[E] {
-	enumSize := Size[E]()
+	return NewUnsafeEnumSetIn[E](defaultRegistry)
+}
+
+// NewUnsafeEnumSetIn Create an empty set of the members of E in the registry reg and its ancestors, see NewUnsafeEnumSet
+func NewUnsafeEnumSetIn[E EnumDefinition](reg *Registry) *UnsafeEnumSet[E] {
+	enumSize := SizeIn[E](reg)
 	return &UnsafeEnumSet[E]{
+		reg:      reg,
 		enumSize: enumSize,
 		elements: make([]uint64, (enumSize+63)>>6 /*除以64并向上取整*/),
 	}
 }
 
 type UnsafeEnumSet[E EnumDefinition] struct {
+	// reg 成员所属的注册表，零值集合使用默认注册表
+	reg *Registry
+	// cache 校验成员的索引，只在遇到未知成员时重新获取，Add不必每次查找注册表
+	cache    *typeCache[E]
 	enumSize int
 	cap      int
 	// elements 低位放低位枚举
@@ -63,26 +78,58 @@ type UnsafeEnumSet[E EnumDefinition] struct {
 	elements []uint64
 	// 已存放枚举数量
 	len int
+	// encoding MarshalJSON与MarshalText使用的编码，默认编码为名称
+	encoding SetEncoding
 }
 
 func (set *UnsafeEnumSet[E]) String() string {
 	return "[" + strings.Join(set.Names(), ",") + "]"
 }
 
-func (set *UnsafeEnumSet[E]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(set.Names())
+// Add Add e to the set. It panics if e is not a registered member of E in the registry of the set,
+// such as the zero value, a nil pointer or an instance not created by NewEnum
+func (set *UnsafeEnumSet[E]) Add(e E) bool {
+	return set.addIdx(set.member(e))
 }
 
-func (set *UnsafeEnumSet[E]) Add(e E) bool {
-	return set.addIdx(e.Ordinal())
+// registry The registry of the members, it can be called on a nil receiver
+func (set *UnsafeEnumSet[E]) registry() *Registry {
+	if set == nil || set.reg == nil {
+		return defaultRegistry
+	}
+	return set.reg
+}
+
+// member Return the ordinal of e, or panic if e is not a registered member
+func (set *UnsafeEnumSet[E]) member(e E) int {
+	if set.cache != nil {
+		if ordinal, ok := set.cache.ordinalOf(e); ok {
+			return ordinal
+		}
+	}
+	// 成员可能在索引获取之后注册
+	set.cache = cacheOf[E](set.registry())
+	return set.cache.mustOrdinalOf(e)
+}
+
+// lookup Return the ordinal of e, or false if e is not a registered member, such as the zero value.
+// Unlike member, the refreshed index is not kept, so reading a set does not modify it
+func (set *UnsafeEnumSet[E]) lookup(e E) (int, bool) {
+	if set.cache != nil {
+		if ordinal, ok := set.cache.ordinalOf(e); ok {
+			return ordinal, true
+		}
+	}
+	return cacheOf[E](set.registry()).ordinalOf(e)
 }
 
 func (set *UnsafeEnumSet[E]) addIdx(ordinal int) bool {
 	// set.elements[i]
 	i := ordinal >> 6
+	set.grow(i + 1)
 	old := set.elements[i]
-	// 等价于 elements[i] |= (uint64(1) << (ordinal % 64))
-	set.elements[i] |= uint64(1) << ordinal
+	// 等价于 elements[i] |= (uint64(1) << (ordinal % 64))，Go的移位不会对位数取模，移位数不小于64时结果为0
+	set.elements[i] |= uint64(1) << (ordinal & 63)
 	added := old != set.elements[i]
 	if added {
 		set.len++
@@ -90,9 +137,10 @@ func (set *UnsafeEnumSet[E]) addIdx(ordinal int) bool {
 	return added
 }
 
+// AddRange Add the members from begin to end in ordinal order, both inclusive. It panics like Add if begin or end is invalid
 func (set *UnsafeEnumSet[E]) AddRange(begin, end E) int {
 	cnt := 0
-	for i := begin.Ordinal(); i <= end.Ordinal(); i++ {
+	for i, last := set.member(begin), set.member(end); i <= last; i++ {
 		if set.addIdx(i) {
 			cnt++
 		}
@@ -100,10 +148,28 @@ func (set *UnsafeEnumSet[E]) AddRange(begin, end E) int {
 	return cnt
 }
 
+// grow Extend the words for the members registered after the set is created
+func (set *UnsafeEnumSet[E]) grow(words int) {
+	if words <= len(set.elements) {
+		return
+	}
+	// 按当前注册的成员数扩容，避免连续注册的成员逐个扩容
+	set.enumSize = SizeIn[E](set.registry())
+	if n := (set.enumSize + 63) >> 6; n > words {
+		words = n
+	}
+	elements := make([]uint64, words)
+	copy(elements, set.elements)
+	set.elements = elements
+}
+
 func (set *UnsafeEnumSet[E]) removeIdx(ordinal int) bool {
 	i := ordinal >> 6
+	if ordinal < 0 || i >= len(set.elements) {
+		return false
+	}
 	old := set.elements[i]
-	set.elements[i] &= ^(uint64(1) << ordinal)
+	set.elements[i] &= ^(uint64(1) << (ordinal & 63))
 	deleted := old != set.elements[i]
 	if deleted {
 		set.len--
@@ -112,12 +178,18 @@ func (set *UnsafeEnumSet[E]) removeIdx(ordinal int) bool {
 }
 
 func (set *UnsafeEnumSet[E]) Remove(e E) bool {
-	return set.removeIdx(e.Ordinal())
+	ordinal, ok := set.lookup(e)
+	return ok && set.removeIdx(ordinal)
 }
 
 func (set *UnsafeEnumSet[E]) RemoveRange(begin, end E) int {
+	from, ok := set.lookup(begin)
+	to, ok2 := set.lookup(end)
+	if !ok || !ok2 {
+		return 0
+	}
 	cnt := 0
-	for i := begin.Ordinal(); i <= end.Ordinal(); i++ {
+	for i := from; i <= to; i++ {
 		if set.removeIdx(i) {
 			cnt++
 		}
@@ -142,7 +214,8 @@ func (set *UnsafeEnumSet[E]) Clear() {
 
 func (set *UnsafeEnumSet[E]) Contains(enums ...E) bool {
 	for _, e := range enums {
-		if set.elements[e.Ordinal()>>6]&(uint64(1)<<e.Ordinal()) == 0 {
+		ordinal, ok := set.lookup(e)
+		if !ok || ordinal>>6 >= len(set.elements) || set.elements[ordinal>>6]&(uint64(1)<<(ordinal&63)) == 0 {
 			return false
 		}
 	}
@@ -153,7 +226,11 @@ func (set *UnsafeEnumSet[E]) Contains(enums ...E) bool {
 func (set *UnsafeEnumSet[E]) ContainsAll(enumSet EnumSet[E]) bool {
 	if es, ok := enumSet.(*UnsafeEnumSet[E]); ok {
 		for i := 0; i < len(es.elements); i++ {
-			if es.elements[i]&set.elements[i] != es.elements[i] {
+			var word uint64
+			if i < len(set.elements) {
+				word = set.elements[i]
+			}
+			if es.elements[i]&word != es.elements[i] {
 				return false
 			}
 		}
@@ -174,15 +251,100 @@ func (set *UnsafeEnumSet[E]) Equals(enumSet EnumSet[E]) bool {
 	return set.ContainsAll(enumSet) && enumSet.ContainsAll(set)
 }
 
+// Each Iterate the elements in ordinal order by walking the set bits word by word,
+// so the cost depends on the number of elements rather than the size of the enumeration
 func (set *UnsafeEnumSet[E]) Each(f func(e E) bool) {
-	allEnums := Values[E]()
-	for _, e := range allEnums {
-		if set.Contains(e) {
-			if !f(e) {
-				break
+	values := ValuesViewIn[E](set.registry())
+	for i, word := range set.elements {
+		for word != 0 {
+			ordinal := i<<6 + bits.TrailingZeros64(word)
+			if ordinal >= len(values) || !f(values[ordinal]) {
+				return
 			}
+			// 清除最低位的1
+			word &= word - 1
+		}
+	}
+}
+
+// Min The element with the smallest ordinal, return false if the set is empty
+func (set *UnsafeEnumSet[E]) Min() (e E, ok bool) {
+	return set.nextSetFrom(0)
+}
+
+// Max The element with the largest ordinal, return false if the set is empty
+func (set *UnsafeEnumSet[E]) Max() (e E, ok bool) {
+	for i := len(set.elements) - 1; i >= 0; i-- {
+		if word := set.elements[i]; word != 0 {
+			return ValueOfOrdinalIn[E](set.registry(), i<<6+63-bits.LeadingZeros64(word))
 		}
 	}
+	return
+}
+
+// NextSetAfter The element with the smallest ordinal greater than the ordinal of e,
+// return false if not found or e is not a registered member
+func (set *UnsafeEnumSet[E]) NextSetAfter(e E) (next E, ok bool) {
+	ordinal, member := set.lookup(e)
+	if !member {
+		return
+	}
+	return set.nextSetFrom(ordinal + 1)
+}
+
+// nextSetFrom The element with the smallest ordinal not less than ordinal
+func (set *UnsafeEnumSet[E]) nextSetFrom(ordinal int) (e E, ok bool) {
+	i := ordinal >> 6
+	if ordinal < 0 || i >= len(set.elements) {
+		return
+	}
+	// 屏蔽当前字中低于ordinal的位
+	word := set.elements[i] & (^uint64(0) << (ordinal & 63))
+	for {
+		if word != 0 {
+			return ValueOfOrdinalIn[E](set.registry(), i<<6+bits.TrailingZeros64(word))
+		}
+		i++
+		if i >= len(set.elements) {
+			return
+		}
+		word = set.elements[i]
+	}
+}
+
+// Rank The number of elements whose ordinals are less than the ordinal of e, -1 if e is not a registered member
+func (set *UnsafeEnumSet[E]) Rank(e E) int {
+	ordinal, ok := set.lookup(e)
+	if !ok {
+		return -1
+	}
+	rank := 0
+	for i, word := range set.elements {
+		if i == ordinal>>6 {
+			return rank + bits.OnesCount64(word&(uint64(1)<<(ordinal&63)-1))
+		}
+		rank += bits.OnesCount64(word)
+	}
+	return rank
+}
+
+// Select The k-th element in ordinal order, starting from zero, return false if k is out of range
+func (set *UnsafeEnumSet[E]) Select(k int) (e E, ok bool) {
+	if k < 0 {
+		return
+	}
+	for i, word := range set.elements {
+		n := bits.OnesCount64(word)
+		if k >= n {
+			k -= n
+			continue
+		}
+		for ; k > 0; k-- {
+			word &= word - 1
+		}
+		return ValueOfOrdinalIn[E](set.registry(), i<<6+bits.TrailingZeros64(word))
+	}
+	return
 }
 
 func (set *UnsafeEnumSet[E]) Names() []string {
@@ -196,10 +358,35 @@ func (set *UnsafeEnumSet[E]) Names() []string {
 
 func (set *UnsafeEnumSet[E]) Clone() EnumSet[E] {
 	res := &UnsafeEnumSet[E]{
+		reg:      set.reg,
+		cache:    set.cache,
 		enumSize: set.enumSize,
 		len:      set.len,
+		encoding: set.encoding,
 		elements: make([]uint64, len(set.elements)),
 	}
 	copy(res.elements, set.elements)
 	return res
 }
+
+// enumSetBinder Build and fill an EnumSet without knowing its generic type, used by reflection-based binders such as Bind
+type enumSetBinder interface {
+	// newSet Create an empty set of the same type, it can be called on a nil receiver
+	newSet() enumSetBinder
+	// enumType The reflect.Type of the set element
+	enumType() reflect.Type
+	// addEnum Add an element, e must be of the set element type
+	addEnum(e EnumDefinition) bool
+}
+
+func (set *UnsafeEnumSet[E]) newSet() enumSetBinder {
+	return NewUnsafeEnumSetIn[E](set.registry())
+}
+
+func (set *UnsafeEnumSet[E]) enumType() reflect.Type {
+	return reflect.TypeOf((*E)(nil)).Elem()
+}
+
+func (set *UnsafeEnumSet[E]) addEnum(e EnumDefinition) bool {
+	return set.Add(e.(E))
+}
@@ -0,0 +1,283 @@
+package goenum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SetEncoding How an UnsafeEnumSet is marshalled by MarshalJSON and MarshalText.
+// Unmarshalling accepts all encodings regardless of the setting
+type SetEncoding int
+
+const (
+	// EncodeNames The names of the members, a JSON array such as ["Decl","Select"], or text such as Decl,Select
+	EncodeNames SetEncoding = iota
+	// EncodeHex A hexadecimal bitmask such as "0x20001", the bit i stands for the member with ordinal i
+	EncodeHex
+	// EncodeInt A decimal bitmask, a JSON number such as 131073. Note that JavaScript loses precision above 2^53
+	EncodeInt
+)
+
+// UnknownMembersError The input decoded into an EnumSet contains names or bits that are not members of the enumeration
+type UnknownMembersError struct {
+	// Type The enumeration type, such as internal.Permission
+	Type string
+	// Names The unknown names
+	Names []string
+	// Ordinals The set bits of a bitmask beyond the last member. There are at most 3 of them,
+	// as a bitmask with more digits than the members need is rejected before parsing
+	Ordinals []int
+}
+
+func (e *UnknownMembersError) Error() string {
+	var parts []string
+	if len(e.Names) > 0 {
+		names := make([]string, 0, len(e.Names))
+		for _, name := range e.Names {
+			names = append(names, strconv.Quote(name))
+		}
+		parts = append(parts, "names "+strings.Join(names, ", "))
+	}
+	if len(e.Ordinals) > 0 {
+		ordinals := make([]string, 0, len(e.Ordinals))
+		for _, ordinal := range e.Ordinals {
+			ordinals = append(ordinals, strconv.Itoa(ordinal))
+		}
+		parts = append(parts, "ordinals "+strings.Join(ordinals, ", "))
+	}
+	return "goenum: unknown members of " + e.Type + ": " + strings.Join(parts, "; ")
+}
+
+// ParseEnumSet Parse a set from the String format such as [Decl,Select], comma-separated names such as Decl,Select,
+// or a bitmask such as 0x20001, 131073 or 0b101. Names are matched case-insensitively like ValueOfIgnoreCase,
+// and take precedence over bitmasks, so a member named 200 is parsed by name.
+// All unknown names and bits are reported together by an *UnknownMembersError
+func ParseEnumSet[E EnumDefinition](s string) (*UnsafeEnumSet[E], error) {
+	return ParseEnumSetIn[E](defaultRegistry, s)
+}
+
+// ParseEnumSetIn Parse a set of the members of E in the registry reg and its ancestors, see ParseEnumSet
+func ParseEnumSetIn[E EnumDefinition](reg *Registry, s string) (*UnsafeEnumSet[E], error) {
+	set := NewUnsafeEnumSetIn[E](reg)
+	if err := set.parse(s); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// WithEncoding Set the encoding used by MarshalJSON and MarshalText, return the set itself
+func (set *UnsafeEnumSet[E]) WithEncoding(encoding SetEncoding) *UnsafeEnumSet[E] {
+	set.encoding = encoding
+	return set
+}
+
+func (set *UnsafeEnumSet[E]) MarshalJSON() ([]byte, error) {
+	switch set.encoding {
+	case EncodeHex:
+		return json.Marshal(set.hex())
+	case EncodeInt:
+		return []byte(set.bitmask().String()), nil
+	}
+	return json.Marshal(set.Names())
+}
+
+// UnmarshalJSON Accept an array of names, a string in any format of ParseEnumSet, or a number as bitmask.
+// The members of the set are replaced, and kept unchanged if failed
+func (set *UnsafeEnumSet[E]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var names []string
+		if err := json.Unmarshal(data, &names); err != nil {
+			return err
+		}
+		return set.parse(strings.Join(names, ","))
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return set.parse(s)
+	}
+	if ok, err := set.parseBitmask(string(data)); ok {
+		return err
+	}
+	return &json.UnmarshalTypeError{Value: "number " + string(data), Type: reflect.TypeOf(set)}
+}
+
+// MarshalText Marshal to comma-separated names, or the bitmask according to the encoding
+func (set *UnsafeEnumSet[E]) MarshalText() ([]byte, error) {
+	switch set.encoding {
+	case EncodeHex:
+		return []byte(set.hex()), nil
+	case EncodeInt:
+		return []byte(set.bitmask().String()), nil
+	}
+	return []byte(strings.Join(set.Names(), ",")), nil
+}
+
+// UnmarshalText Accept any format of ParseEnumSet. The members of the set are replaced, and kept unchanged if failed
+func (set *UnsafeEnumSet[E]) UnmarshalText(text []byte) error {
+	return set.parse(string(text))
+}
+
+// parse Replace the members of the set by s, see ParseEnumSet
+func (set *UnsafeEnumSet[E]) parse(s string) error {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if isBitmaskLiteral(s) {
+		// 名称优先，只有没有同名成员时才按位图解析，如名为200的成员
+		if _, valid := ValueOfIgnoreCaseIn[E](set.registry(), s); !valid {
+			if ok, err := set.parseBitmask(s); ok {
+				return err
+			}
+		}
+	}
+	parsed := NewUnsafeEnumSetIn[E](set.registry())
+	var unknown []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e, valid := ValueOfIgnoreCaseIn[E](set.registry(), name)
+		if !valid {
+			unknown = append(unknown, name)
+			continue
+		}
+		parsed.addIdx(e.Ordinal())
+	}
+	if len(unknown) > 0 {
+		return &UnknownMembersError{Type: cacheOf[E](set.registry()).typeName, Names: unknown}
+	}
+	set.elements, set.len = parsed.elements, parsed.len
+	return nil
+}
+
+// isBitmaskLiteral Whether s is an integer literal such as 0x20001, 131073 or 0b101, which ParseEnumSet parses as a bitmask
+// unless a member has the name
+func isBitmaskLiteral(s string) bool {
+	_, _, ok := bitmaskDigits(s)
+	return ok && s[0] >= '0' && s[0] <= '9'
+}
+
+// bitmaskDigits Return the base and the number of significant digits of s, an integer literal in the syntax of
+// big.Int.SetString with base 0. Return false if s contains characters other than the prefix, the digits of the base and _
+func bitmaskDigits(s string) (base, digits int, ok bool) {
+	base = 10
+	if len(s) > 1 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			base, s = 16, s[2:]
+		case 'b', 'B':
+			base, s = 2, s[2:]
+		case 'o', 'O':
+			base, s = 8, s[2:]
+		default:
+			base = 8
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' {
+			continue
+		}
+		if digitValue(c) >= base {
+			return 0, 0, false
+		}
+		// 不计前导零
+		if digits > 0 || c != '0' {
+			digits++
+		}
+	}
+	return base, digits, len(s) > 0
+}
+
+func digitValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return 16
+}
+
+// maxBitmaskDigits The number of digits in base of the largest bitmask of size bits
+func maxBitmaskDigits(base, size int) int {
+	switch base {
+	case 2:
+		return size
+	case 8:
+		return (size + 2) / 3
+	case 16:
+		return (size + 3) / 4
+	}
+	// 2^size-1的十进制位数为floor(size*log10(2))+1
+	return size*30103/100000 + 1
+}
+
+// parseBitmask Replace the members of the set by the bitmask s, return false if s is not an integer literal.
+// A bitmask with more digits than the members need is rejected before parsing, so a long input fails fast
+func (set *UnsafeEnumSet[E]) parseBitmask(s string) (bool, error) {
+	base, digits, ok := bitmaskDigits(s)
+	if !ok {
+		return false, nil
+	}
+	if size := SizeIn[E](set.registry()); digits > maxBitmaskDigits(base, size) {
+		return true, errors.New("goenum: bitmask of " + strconv.Itoa(digits) + " digits exceeds the " +
+			strconv.Itoa(size) + " members of " + cacheOf[E](set.registry()).typeName)
+	}
+	mask, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return false, nil
+	}
+	return true, set.fromBitmask(mask)
+}
+
+// fromBitmask Replace the members of the set by the set bits of mask
+func (set *UnsafeEnumSet[E]) fromBitmask(mask *big.Int) error {
+	parsed := NewUnsafeEnumSetIn[E](set.registry())
+	size := SizeIn[E](set.registry())
+	var unknown []int
+	for i := 0; i < mask.BitLen(); i++ {
+		if mask.Bit(i) == 0 {
+			continue
+		}
+		if i >= size {
+			unknown = append(unknown, i)
+			continue
+		}
+		parsed.addIdx(i)
+	}
+	if len(unknown) > 0 {
+		return &UnknownMembersError{Type: cacheOf[E](set.registry()).typeName, Ordinals: unknown}
+	}
+	set.elements, set.len = parsed.elements, parsed.len
+	return nil
+}
+
+// bitmask The set as an integer, the bit i stands for the member with ordinal i
+func (set *UnsafeEnumSet[E]) bitmask() *big.Int {
+	mask, word := new(big.Int), new(big.Int)
+	for i := len(set.elements) - 1; i >= 0; i-- {
+		mask.Lsh(mask, 64)
+		mask.Or(mask, word.SetUint64(set.elements[i]))
+	}
+	return mask
+}
+
+func (set *UnsafeEnumSet[E]) hex() string {
+	return "0x" + set.bitmask().Text(16)
+}
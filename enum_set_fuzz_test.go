@@ -0,0 +1,218 @@
+package goenum
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// setModel 基于map的参考模型，用于校验UnsafeEnumSet的行为
+type setModel map[int]bool
+
+func (m setModel) ordinals() []int {
+	res := make([]int, 0, len(m))
+	for ordinal := range m {
+		res = append(res, ordinal)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// setOp 操作序列中的一个操作，每个操作占3个字节：操作码与两个参数
+const (
+	opAdd = iota
+	opAddRange
+	opRemove
+	opRemoveRange
+	opClear
+	opClone
+	opContainsAll
+	opCount
+)
+
+// runSetModel 按ops依次操作UnsafeEnumSet与参考模型，每次操作后比较两者
+func runSetModel(t *testing.T, ops []byte) {
+	values := ValuesView[Wide]()
+	member := func(b byte) Wide {
+		// 参数扩大4倍，覆盖1000个成员的多个字
+		return values[int(b)*4%len(values)]
+	}
+	set, model := NewUnsafeEnumSet[Wide](), setModel{}
+	for i := 0; i+2 < len(ops); i += 3 {
+		a, b := member(ops[i+1]), member(ops[i+2])
+		switch ops[i] % opCount {
+		case opAdd:
+			if got := set.Add(a); got == model[a.Ordinal()] {
+				t.Fatalf("op %d: Add(%s) returned %v", i/3, a.Name(), got)
+			}
+			model[a.Ordinal()] = true
+		case opAddRange:
+			expected := 0
+			for o := a.Ordinal(); o <= b.Ordinal(); o++ {
+				if !model[o] {
+					expected++
+					model[o] = true
+				}
+			}
+			if got := set.AddRange(a, b); got != expected {
+				t.Fatalf("op %d: AddRange(%s, %s) returned %d, expected %d", i/3, a.Name(), b.Name(), got, expected)
+			}
+		case opRemove:
+			if got := set.Remove(a); got != model[a.Ordinal()] {
+				t.Fatalf("op %d: Remove(%s) returned %v", i/3, a.Name(), got)
+			}
+			delete(model, a.Ordinal())
+		case opRemoveRange:
+			expected := 0
+			for o := a.Ordinal(); o <= b.Ordinal(); o++ {
+				if model[o] {
+					expected++
+					delete(model, o)
+				}
+			}
+			if got := set.RemoveRange(a, b); got != expected {
+				t.Fatalf("op %d: RemoveRange(%s, %s) returned %d, expected %d", i/3, a.Name(), b.Name(), got, expected)
+			}
+		case opClear:
+			set.Clear()
+			model = setModel{}
+		case opClone:
+			clone := set.Clone().(*UnsafeEnumSet[Wide])
+			if !clone.Equals(set) || !set.Equals(clone) {
+				t.Fatalf("op %d: Clone %v is not equal to %v", i/3, clone, set)
+			}
+			// 修改原集合不影响克隆
+			set.Add(a)
+			set = clone
+		case opContainsAll:
+			// 以模型中序号在[a, b]之外的成员加上a构造另一个集合
+			other, contains := NewUnsafeEnumSet[Wide](), model[a.Ordinal()]
+			other.Add(a)
+			for o := range model {
+				if o < a.Ordinal() || o > b.Ordinal() {
+					other.Add(values[o])
+				}
+			}
+			if got := set.ContainsAll(other); got != contains {
+				t.Fatalf("op %d: ContainsAll returned %v, expected %v", i/3, got, contains)
+			}
+		}
+		checkSetModel(t, i/3, set, model)
+	}
+}
+
+func checkSetModel(t *testing.T, op int, set *UnsafeEnumSet[Wide], model setModel) {
+	ordinals := model.ordinals()
+	if set.Len() != len(ordinals) || set.IsEmpty() != (len(ordinals) == 0) {
+		t.Fatalf("op %d: Len %d, expected %d", op, set.Len(), len(ordinals))
+	}
+	var each []int
+	set.Each(func(e Wide) bool {
+		each = append(each, e.Ordinal())
+		return true
+	})
+	if len(each) != len(ordinals) {
+		t.Fatalf("op %d: Each iterated %v, expected %v", op, each, ordinals)
+	}
+	for k, o := range ordinals {
+		if each[k] != o {
+			t.Fatalf("op %d: Each iterated %v, expected %v", op, each, ordinals)
+		}
+		e := wideEnums[o]
+		if !set.Contains(e) || set.Rank(e) != k {
+			t.Fatalf("op %d: Contains or Rank of %s is wrong", op, e.Name())
+		}
+		if s, ok := set.Select(k); !ok || s != e {
+			t.Fatalf("op %d: Select(%d) returned %s, expected %s", op, k, s.Name(), e.Name())
+		}
+	}
+	min, minOk := set.Min()
+	max, maxOk := set.Max()
+	if minOk != (len(ordinals) > 0) || maxOk != minOk {
+		t.Fatalf("op %d: Min or Max of empty set", op)
+	}
+	if minOk && (min.Ordinal() != ordinals[0] || max.Ordinal() != ordinals[len(ordinals)-1]) {
+		t.Fatalf("op %d: Min %s Max %s, expected %v", op, min.Name(), max.Name(), ordinals)
+	}
+}
+
+// TestEnumSet_Model 随机操作序列的性质测试，FuzzEnumSet_Ops 在此基础上探索更多序列
+func TestEnumSet_Model(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		ops := make([]byte, 3*r.Intn(100))
+		r.Read(ops)
+		runSetModel(t, ops)
+	}
+	// begin > end 的区间不做任何修改
+	runSetModel(t, []byte{opAdd, 10, 0, opAddRange, 200, 100, opRemoveRange, 20, 0, opRemoveRange, 10, 10})
+}
+
+func FuzzEnumSet_Ops(f *testing.F) {
+	f.Add([]byte{opAdd, 1, 0, opAddRange, 10, 30, opRemove, 15, 0, opRemoveRange, 20, 10, opClone, 3, 0, opContainsAll, 12, 40})
+	f.Add([]byte{opAddRange, 0, 249, opClear, 0, 0, opAdd, 249, 0})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		runSetModel(t, ops)
+	})
+}
+
+func FuzzParseEnumSet(f *testing.F) {
+	for _, s := range []string{"[Decl,Select]", "Decl, If ,range", "0x20001", "131073", "0b101", "[]", "", "Foo,Decl", "0xfffffff", "[0x1]"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		set, err := ParseEnumSet[Statement](s)
+		if err != nil {
+			var unknown *UnknownMembersError
+			if errors.As(err, &unknown) && len(unknown.Names)+len(unknown.Ordinals) > 0 && len(unknown.Ordinals) <= 3 {
+				return
+			}
+			// 位数超过成员数的位图在解析前被拒绝
+			if !strings.Contains(err.Error(), "digits exceeds the") {
+				t.Fatalf("unexpected error %v", err)
+			}
+			return
+		}
+		checkCodecs(t, set)
+	})
+}
+
+func FuzzEnumSet_UnmarshalJSON(f *testing.F) {
+	for _, s := range []string{`["Decl","Select"]`, `"Decl,Select"`, `"0x3"`, `3`, `null`, `[]`, `{}`, `-1`, `["Foo"]`, `1e3`} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		set := NewUnsafeEnumSet[Statement]()
+		if err := set.UnmarshalJSON(data); err != nil {
+			if !set.IsEmpty() {
+				t.Fatalf("the set is modified by the failed unmarshalling: %v", set)
+			}
+			return
+		}
+		checkCodecs(t, set)
+	})
+}
+
+// checkCodecs 所有编码都能往返，且长度与成员一致
+func checkCodecs(t *testing.T, set *UnsafeEnumSet[Statement]) {
+	if set.Len() != len(set.Names()) {
+		t.Fatalf("Len %d mismatches Names %v", set.Len(), set.Names())
+	}
+	parsed, err := ParseEnumSet[Statement](set.String())
+	if err != nil || !parsed.Equals(set) {
+		t.Fatalf("String %s does not round trip: %v", set, err)
+	}
+	for _, encoding := range []SetEncoding{EncodeNames, EncodeHex, EncodeInt} {
+		data, err := json.Marshal(set.Clone().(*UnsafeEnumSet[Statement]).WithEncoding(encoding))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := NewUnsafeEnumSet[Statement]()
+		if err := json.Unmarshal(data, decoded); err != nil || !decoded.Equals(set) {
+			t.Fatalf("%s does not round trip: %v", data, err)
+		}
+	}
+}
@@ -8,6 +8,7 @@ package goenum
 import (
 	"encoding/json"
 	"github.com/stretchr/testify/require"
+	"strconv"
 	"testing"
 )
 
@@ -124,3 +125,240 @@ func BenchmarkUnsafeEnumSet_Contains(b *testing.B) {
 		}
 	})
 }
+
+func TestEnumSet_RankSelect(t *testing.T) {
+	set := NewUnsafeEnumSet[Statement]()
+	_, ok := set.Min()
+	require.False(t, ok)
+	_, ok = set.Max()
+	require.False(t, ok)
+	_, ok = set.Select(0)
+	require.False(t, ok)
+	set.Add(Labeled)
+	set.Add(Go)
+	set.Add(Range)
+	min, _ := set.Min()
+	max, _ := set.Max()
+	require.Equal(t, Labeled, min)
+	require.Equal(t, Range, max)
+	next, ok := set.NextSetAfter(Labeled)
+	require.True(t, ok)
+	require.Equal(t, Go, next)
+	next, ok = set.NextSetAfter(Decl)
+	require.True(t, ok)
+	require.Equal(t, Labeled, next)
+	_, ok = set.NextSetAfter(Range)
+	require.False(t, ok)
+	require.Equal(t, 0, set.Rank(Decl))
+	require.Equal(t, 0, set.Rank(Labeled))
+	require.Equal(t, 1, set.Rank(Go))
+	require.Equal(t, 2, set.Rank(For))
+	for k, e := range []Statement{Labeled, Go, Range} {
+		s, ok := set.Select(k)
+		require.True(t, ok)
+		require.Equal(t, e, s)
+		require.Equal(t, k, set.Rank(e))
+	}
+	_, ok = set.Select(3)
+	require.False(t, ok)
+	_, ok = set.Select(-1)
+	require.False(t, ok)
+}
+
+// Wide 多个uint64字的大枚举，用于测试按字遍历
+type Wide struct {
+	Enum
+}
+
+var wideEnums = func() (res []Wide) {
+	for i := 0; i < 1000; i++ {
+		res = append(res, NewEnum[Wide]("W"+strconv.Itoa(i)))
+	}
+	return
+}()
+
+// sparseWideSet 1000个成员中只有3个元素
+var sparseWideSet = func() *UnsafeEnumSet[Wide] {
+	set := NewUnsafeEnumSet[Wide]()
+	set.Add(wideEnums[3])
+	set.Add(wideEnums[500])
+	set.Add(wideEnums[999])
+	return set
+}()
+
+func TestEnumSet_Wide(t *testing.T) {
+	var names []string
+	sparseWideSet.Each(func(e Wide) bool {
+		names = append(names, e.Name())
+		return true
+	})
+	require.Equal(t, []string{"W3", "W500", "W999"}, names)
+	max, _ := sparseWideSet.Max()
+	require.Equal(t, wideEnums[999], max)
+	next, _ := sparseWideSet.NextSetAfter(wideEnums[3])
+	require.Equal(t, wideEnums[500], next)
+	require.Equal(t, 1, sparseWideSet.Rank(wideEnums[500]))
+	require.Equal(t, 2, sparseWideSet.Rank(wideEnums[501]))
+	s, _ := sparseWideSet.Select(2)
+	require.Equal(t, wideEnums[999], s)
+}
+
+// eachByValues 按字遍历之前的实现：遍历所有枚举并逐个判断是否在集合中
+func eachByValues[E EnumDefinition](set *UnsafeEnumSet[E], f func(e E) bool) {
+	for _, e := range Values[E]() {
+		if set.Contains(e) {
+			if !f(e) {
+				break
+			}
+		}
+	}
+}
+
+// BenchmarkUnsafeEnumSet_Each
+//
+//	go test -run=^$ -bench=BenchmarkUnsafeEnumSet_Each -benchmem
+func BenchmarkUnsafeEnumSet_Each(b *testing.B) {
+	count := func(e Wide) bool { return true }
+	b.Run("sparse/values", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			eachByValues(sparseWideSet, count)
+		}
+	})
+	b.Run("sparse/bits", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sparseWideSet.Each(count)
+		}
+	})
+	full := NewUnsafeEnumSet[Wide]()
+	full.AddRange(wideEnums[0], wideEnums[999])
+	b.Run("full/values", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			eachByValues(full, count)
+		}
+	})
+	b.Run("full/bits", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			full.Each(count)
+		}
+	})
+}
+
+func BenchmarkUnsafeEnumSet_RankSelect(b *testing.B) {
+	b.Run("Rank", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = sparseWideSet.Rank(wideEnums[999])
+		}
+	})
+	b.Run("Select", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = sparseWideSet.Select(2)
+		}
+	})
+	b.Run("NextSetAfter", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = sparseWideSet.NextSetAfter(wideEnums[4])
+		}
+	})
+}
+
+// Late 创建集合之后才注册的成员，模拟动态成员
+type Late struct {
+	Enum
+}
+
+var LateFirst = NewEnum[Late]("LateFirst")
+
+func TestEnumSet_LateRegistration(t *testing.T) {
+	set := NewUnsafeEnumSet[Late]()
+	require.True(t, set.Add(LateFirst))
+	var late []Late
+	for i := 0; i < 130; i++ {
+		late = append(late, NewEnum[Late]("Late"+strconv.Itoa(i)))
+	}
+	last := late[len(late)-1]
+	require.Equal(t, 130, last.Ordinal())
+	require.False(t, set.Contains(last))
+	require.False(t, set.Remove(last))
+	require.True(t, set.Add(last))
+	require.True(t, set.Contains(LateFirst, last))
+	require.Equal(t, 2, set.Len())
+	require.Equal(t, "[LateFirst,Late129]", set.String())
+	max, _ := set.Max()
+	require.Equal(t, last, max)
+	// 较短的集合与较长的集合比较
+	short := NewUnsafeEnumSet[Late]()
+	short.Add(LateFirst)
+	require.True(t, set.ContainsAll(short))
+	require.False(t, short.ContainsAll(set))
+	require.False(t, short.Equals(set))
+	require.Equal(t, 67, short.AddRange(late[63], last))
+	require.Equal(t, 68, short.Len())
+	require.True(t, short.ContainsAll(set))
+}
+
+func TestEnumSet_InvalidMember(t *testing.T) {
+	set := NewUnsafeEnumSet[Statement]()
+	require.PanicsWithValue(t, `goenum: "" is not a registered member of goenum.Statement`, func() {
+		set.Add(Statement{})
+	})
+	reg := NewRegistry(nil)
+	foreign := NewEnumIn[Statement](reg, "Foreign")
+	require.Panics(t, func() { set.Add(foreign) })
+	require.Panics(t, func() { set.AddRange(Decl, foreign) })
+	require.True(t, set.IsEmpty())
+	// 不属于集合的值不会panic
+	require.False(t, set.Contains(Statement{}))
+	require.False(t, set.Remove(foreign))
+	// 零值和foreign的序号都是0，不能当作集合中的Decl
+	set.Add(Decl)
+	set.Add(Labeled)
+	require.False(t, set.Contains(Statement{}))
+	require.False(t, set.Contains(foreign))
+	require.False(t, set.Remove(Statement{}))
+	require.False(t, set.Remove(foreign))
+	require.Equal(t, 0, set.RemoveRange(foreign, Range))
+	require.Equal(t, 0, set.RemoveRange(Decl, Statement{}))
+	_, ok := set.NextSetAfter(foreign)
+	require.False(t, ok)
+	require.Equal(t, -1, set.Rank(Statement{}))
+	require.Equal(t, -1, set.Rank(foreign))
+	require.Equal(t, "[Decl,Labeled]", set.String())
+	set.Clear()
+
+	// 其他注册表的成员加入该注册表的集合
+	other := NewUnsafeEnumSetIn[Statement](reg)
+	require.True(t, other.Add(foreign))
+	require.Panics(t, func() { other.Add(Decl) })
+	later := NewEnumIn[Statement](reg, "Later")
+	require.Equal(t, 1, other.AddRange(foreign, later))
+	require.Equal(t, "[Foreign,Later]", other.String())
+	require.Equal(t, "[Foreign,Later]", other.Clone().String())
+	parsed, err := ParseEnumSetIn[Statement](reg, "later")
+	require.Nil(t, err)
+	require.Equal(t, "[Later]", parsed.String())
+}
+
+func BenchmarkUnsafeEnumSet_Add(b *testing.B) {
+	set := NewUnsafeEnumSet[Statement]()
+	for i := 0; i < b.N; i++ {
+		set.Add(Select)
+	}
+}
+
+func TestEnumSet_WideBitmask(t *testing.T) {
+	set := NewUnsafeEnumSet[Wide]()
+	set.Add(wideEnums[0])
+	set.Add(wideEnums[99])
+	data, err := set.WithEncoding(EncodeHex).MarshalJSON()
+	require.Nil(t, err)
+	require.Equal(t, `"0x8000000000000000000000001"`, string(data))
+	decoded := NewUnsafeEnumSet[Wide]()
+	require.Nil(t, json.Unmarshal(data, decoded))
+	require.True(t, set.Equals(decoded))
+	data, err = set.WithEncoding(EncodeInt).MarshalJSON()
+	require.Nil(t, err)
+	require.Equal(t, "633825300114114700748351602689", string(data))
+	decoded.Clear()
+	require.Nil(t, json.Unmarshal(data, decoded))
+	require.True(t, set.Equals(decoded))
+}
@@ -0,0 +1,359 @@
+package goenum
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ExportOptions Options of Export
+type ExportOptions struct {
+	// Lang Target language: typescript, python or java
+	Lang string
+	// PkgPaths Only export the enumeration types declared in these Go packages, export all types if empty
+	PkgPaths []string
+	// JavaPackage Package prefix of the generated Java files, such as com.example.enums
+	JavaPackage string
+}
+
+// ExportFile A source file generated by Export
+type ExportFile struct {
+	// Path Relative path of the file, derived from the import path of the Go package,
+	// such as github.com/lvyahui8/goenum/internal.ts or com/example/github_com/lvyahui8/goenum/internal/TradeState.java
+	Path    string
+	Content []byte
+}
+
+// exportType An enumeration type to export
+type exportType struct {
+	pkgPath string
+	name    string
+	key     string
+	enums   []EnumDefinition
+	// attrNames Attribute names shared by all members
+	attrNames []string
+}
+
+// Export Generate the source code of the registered enumeration types in another language:
+//   - typescript: a string union type and a const object with ordinals and attributes for each type, one file per Go package
+//   - python: an Enum class for each type, one module per Go package
+//   - java: an enum for each type, one file per type
+//
+// Files and packages are named after the full import path of the Go package, such as github.com/lvyahui8/goenum/internal.ts,
+// the Python module github_com.lvyahui8.goenum.internal and the Java package com.example.github_com.lvyahui8.goenum.internal,
+// so packages with the same name never overwrite each other. Members are generated in ordinal order,
+// and attributes (see Attributes) are generated as fields, so the output is deterministic and suitable for checking in.
+func Export(opts ExportOptions) ([]ExportFile, error) {
+	types := exportTypes(opts.PkgPaths)
+	switch strings.ToLower(opts.Lang) {
+	case "ts", "typescript":
+		return exportByPackage(types, ".ts", func(pkgPath string) string {
+			return pkgPath
+		}, writeTypeScript)
+	case "py", "python":
+		return exportByPackage(types, ".py", func(pkgPath string) string {
+			return path.Join(packageElems(pkgPath, pythonKeywords)...)
+		}, writePython)
+	case "java":
+		var files []ExportFile
+		paths := make(map[string]string)
+		for _, et := range types {
+			pkg := strings.Join(append(strings.FieldsFunc(opts.JavaPackage, func(r rune) bool { return r == '.' }),
+				packageElems(et.pkgPath, javaKeywords)...), ".")
+			if err := checkExportPath(paths, pkg, et.pkgPath); err != nil {
+				return nil, err
+			}
+			buf := &bytes.Buffer{}
+			writeJava(buf, pkg, et)
+			files = append(files, ExportFile{
+				Path:    path.Join(strings.ReplaceAll(pkg, ".", "/"), identifier(et.name, javaKeywords)+".java"),
+				Content: buf.Bytes(),
+			})
+		}
+		return files, nil
+	}
+	return nil, errors.New("goenum: unsupported export language " + strconv.Quote(opts.Lang))
+}
+
+// packageElems Convert the elements of the import path to identifiers, such as [github_com lvyahui8 goenum internal]
+func packageElems(pkgPath string, reserved map[string]bool) []string {
+	elems := strings.Split(pkgPath, "/")
+	for i, elem := range elems {
+		elems[i] = identifier(elem, reserved)
+	}
+	return elems
+}
+
+// checkExportPath Report the Go packages converted to the same file or package, such as a/b-c and a/b_c.
+// paths maps the generated names to the import paths seen before
+func checkExportPath(paths map[string]string, name string, pkgPath string) error {
+	if seen, exist := paths[name]; exist && seen != pkgPath {
+		return errors.New("goenum: packages " + seen + " and " + pkgPath + " are both exported as " + name)
+	}
+	paths[name] = pkgPath
+	return nil
+}
+
+func exportTypes(pkgPaths []string) (types []*exportType) {
+	for _, key := range typeKeys() {
+		t := typeOfKey(key)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if len(pkgPaths) > 0 && !containsString(pkgPaths, t.PkgPath()) {
+			continue
+		}
+		et := &exportType{pkgPath: t.PkgPath(), name: t.Name(), key: key, enums: loadRegistry().type2enums[key]}
+		for _, attr := range Attributes(et.enums[0]) {
+			et.attrNames = append(et.attrNames, attr.Name)
+		}
+		types = append(types, et)
+	}
+	return sortByPackage(types)
+}
+
+// sortByPackage Sort the types by import path, keeping the order of the types in a package.
+// The types of packages with the same name, such as a/internal and b/internal, may interleave when sorted by type key
+func sortByPackage(types []*exportType) []*exportType {
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].pkgPath < types[j].pkgPath
+	})
+	return types
+}
+
+// exportByPackage Generate a file for each Go package, fileName gives the file path without ext
+func exportByPackage(types []*exportType, ext string, fileName func(pkgPath string) string,
+	write func(buf *bytes.Buffer, et *exportType)) (files []ExportFile, err error) {
+	var buf *bytes.Buffer
+	paths := make(map[string]string)
+	for i, et := range types {
+		if i == 0 || types[i-1].pkgPath != et.pkgPath {
+			name := fileName(et.pkgPath) + ext
+			if err = checkExportPath(paths, name, et.pkgPath); err != nil {
+				return nil, err
+			}
+			buf = &bytes.Buffer{}
+			buf.WriteString(fileHeader(ext))
+			files = append(files, ExportFile{Path: name})
+		} else if ext == ".py" {
+			buf.WriteString("\n\n")
+		} else {
+			buf.WriteString("\n")
+		}
+		write(buf, et)
+		files[len(files)-1].Content = buf.Bytes()
+	}
+	return
+}
+
+func fileHeader(ext string) string {
+	comment := "//"
+	if ext == ".py" {
+		comment = "#"
+	}
+	header := comment + " Code generated by goenum export. DO NOT EDIT.\n\n"
+	if ext == ".py" {
+		header += "from enum import Enum\n\n\n"
+	}
+	return header
+}
+
+func writeTypeScript(buf *bytes.Buffer, et *exportType) {
+	name := identifier(et.name, nil)
+	fmt.Fprintf(buf, "// %s\nexport type %s =", et.key, name)
+	for i, e := range et.enums {
+		if i > 0 {
+			buf.WriteString(" |")
+		}
+		buf.WriteString(" " + strconv.Quote(e.Name()))
+	}
+	fmt.Fprintf(buf, ";\n\nexport const %s = {\n", name)
+	for _, e := range et.enums {
+		fmt.Fprintf(buf, "  %s: { name: %s, ordinal: %d", strconv.Quote(e.Name()), strconv.Quote(e.Name()), e.Ordinal())
+		for _, attr := range Attributes(e) {
+			fmt.Fprintf(buf, ", %s: %s", attr.Name, literal(attr.Value, "[", "]", "true", "false"))
+		}
+		buf.WriteString(" },\n")
+	}
+	buf.WriteString("} as const;\n")
+}
+
+var pythonKeywords = keywords("False None True and as assert async await break class continue def del elif else except " +
+	"finally for from global if import in is lambda nonlocal not or pass raise return try while with yield")
+
+func writePython(buf *bytes.Buffer, et *exportType) {
+	fmt.Fprintf(buf, "class %s(Enum):\n    \"\"\"%s\"\"\"\n\n", identifier(et.name, pythonKeywords), et.key)
+	for _, e := range et.enums {
+		fmt.Fprintf(buf, "    %s = (%s, %d", identifier(e.Name(), pythonKeywords), strconv.Quote(e.Name()), e.Ordinal())
+		for _, attr := range Attributes(e) {
+			buf.WriteString(", " + literal(attr.Value, "(", ",)", "True", "False"))
+		}
+		buf.WriteString(")\n")
+	}
+	buf.WriteString("\n    def __init__(self, go_name, go_ordinal")
+	for _, name := range et.attrNames {
+		buf.WriteString(", " + snakeCase(name))
+	}
+	buf.WriteString("):\n        self.go_name = go_name\n        self.go_ordinal = go_ordinal\n")
+	for _, name := range et.attrNames {
+		fmt.Fprintf(buf, "        self.%s = %s\n", snakeCase(name), snakeCase(name))
+	}
+}
+
+var javaKeywords = keywords("abstract assert boolean break byte case catch char class const continue default do double " +
+	"else enum extends final finally float for goto if implements import instanceof int interface long native new " +
+	"package private protected public return short static strictfp super switch synchronized this throw throws " +
+	"transient try void volatile while true false null")
+
+func writeJava(buf *bytes.Buffer, pkg string, et *exportType) {
+	name := identifier(et.name, javaKeywords)
+	fmt.Fprintf(buf, "// Code generated by goenum export. DO NOT EDIT.\n\npackage %s;\n\n", pkg)
+	fmt.Fprintf(buf, "/**\n * %s\n */\npublic enum %s {\n", et.key, name)
+	attrTypes := make([]string, len(et.attrNames))
+	for i, e := range et.enums {
+		fmt.Fprintf(buf, "    %s(%s", identifier(e.Name(), javaKeywords), strconv.Quote(e.Name()))
+		for j, attr := range Attributes(e) {
+			attrTypes[j] = javaType(attr.Value)
+			buf.WriteString(", " + javaLiteral(attr.Value))
+		}
+		if i == len(et.enums)-1 {
+			buf.WriteString(");\n\n")
+		} else {
+			buf.WriteString("),\n")
+		}
+	}
+	buf.WriteString("    private final String goName;\n")
+	for i, attr := range et.attrNames {
+		fmt.Fprintf(buf, "    private final %s %s;\n", attrTypes[i], identifier(attr, javaKeywords))
+	}
+	fmt.Fprintf(buf, "\n    %s(String goName", name)
+	for i, attr := range et.attrNames {
+		fmt.Fprintf(buf, ", %s %s", attrTypes[i], identifier(attr, javaKeywords))
+	}
+	buf.WriteString(") {\n        this.goName = goName;\n")
+	for _, attr := range et.attrNames {
+		fmt.Fprintf(buf, "        this.%s = %s;\n", identifier(attr, javaKeywords), identifier(attr, javaKeywords))
+	}
+	buf.WriteString("    }\n\n    public String getGoName() {\n        return goName;\n    }\n")
+	for i, attr := range et.attrNames {
+		getter := "get" + strings.ToUpper(attr[:1]) + attr[1:]
+		if attrTypes[i] == "boolean" && strings.HasPrefix(attr, "is") {
+			getter = attr
+		}
+		fmt.Fprintf(buf, "\n    public %s %s() {\n        return %s;\n    }\n", attrTypes[i], getter, identifier(attr, javaKeywords))
+	}
+	buf.WriteString("}\n")
+}
+
+func javaType(v any) string {
+	switch v.(type) {
+	case string:
+		return "String"
+	case bool:
+		return "boolean"
+	case int64, uint64:
+		return "long"
+	case float64:
+		return "double"
+	}
+	list := v.([]any)
+	elemType := "Object"
+	if len(list) > 0 {
+		elemType = javaType(list[0])
+		switch elemType {
+		case "boolean":
+			elemType = "Boolean"
+		case "long":
+			elemType = "Long"
+		case "double":
+			elemType = "Double"
+		}
+	}
+	return "java.util.List<" + elemType + ">"
+}
+
+func javaLiteral(v any) string {
+	switch value := v.(type) {
+	case int64, uint64:
+		return fmt.Sprint(value) + "L"
+	case []any:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = javaLiteral(item)
+		}
+		return "java.util.List.of(" + strings.Join(items, ", ") + ")"
+	}
+	return literal(v, "", "", "true", "false")
+}
+
+// literal Source code literal of the attribute value, lists are wrapped by begin and end
+func literal(v any, begin, end, trueLiteral, falseLiteral string) string {
+	switch value := v.(type) {
+	case string:
+		return strconv.Quote(value)
+	case bool:
+		if value {
+			return trueLiteral
+		}
+		return falseLiteral
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case []any:
+		if len(value) == 0 {
+			return begin + strings.TrimLeft(end, ",")
+		}
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = literal(item, begin, end, trueLiteral, falseLiteral)
+		}
+		return begin + strings.Join(items, ", ") + end
+	}
+	return fmt.Sprint(v)
+}
+
+func keywords(s string) map[string]bool {
+	res := make(map[string]bool)
+	for _, k := range strings.Fields(s) {
+		res[k] = true
+	}
+	return res
+}
+
+// identifier Convert name to a valid identifier, invalid characters are replaced by underscores
+func identifier(name string, reserved map[string]bool) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		} else if unicode.IsDigit(r) {
+			sb.WriteString("_" + string(r))
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	res := sb.String()
+	if res == "" || reserved[res] {
+		res += "_"
+	}
+	return res
+}
+
+func snakeCase(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return identifier(sb.String(), pythonKeywords)
+}
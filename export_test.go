@@ -0,0 +1,38 @@
+package goenum
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"path"
+	"testing"
+)
+
+func TestExportByPackage(t *testing.T) {
+	// 同名的包a/internal与b/internal，类型键排序后交错
+	types := []*exportType{
+		{pkgPath: "a/internal", name: "Color", key: "internal.Color"},
+		{pkgPath: "b/internal", name: "Level", key: "internal.Level"},
+		{pkgPath: "a/internal", name: "State", key: "internal.State"},
+	}
+	sorted := sortByPackage(types)
+	write := func(buf *bytes.Buffer, et *exportType) {
+		buf.WriteString(et.name)
+	}
+	files, err := exportByPackage(sorted, ".ts", func(pkgPath string) string { return pkgPath }, write)
+	require.Nil(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "a/internal.ts", files[0].Path)
+	require.Equal(t, "// Code generated by goenum export. DO NOT EDIT.\n\nColor\nState", string(files[0].Content))
+	require.Equal(t, "b/internal.ts", files[1].Path)
+
+	// 转换为标识符后相同的包名
+	pyName := func(pkgPath string) string {
+		return path.Join(packageElems(pkgPath, pythonKeywords)...)
+	}
+	require.Equal(t, "github_com/a/class_/_1x", pyName("github.com/a/class/1x"))
+	_, err = exportByPackage(sortByPackage([]*exportType{
+		{pkgPath: "a/b-c", name: "Color"},
+		{pkgPath: "a/b_c", name: "Level"},
+	}), ".py", pyName, write)
+	require.EqualError(t, err, "goenum: packages a/b-c and a/b_c are both exported as a/b_c.py")
+}
@@ -0,0 +1,207 @@
+package goenum
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Completer Provide shell-completion candidates for the value of a command line flag
+type Completer interface {
+	// Complete Returns all candidate values starting with prefix (case-insensitive)
+	Complete(prefix string) []string
+}
+
+// EnumFlag Bind a single enumeration to the standard flag package. It implements flag.Value and flag.Getter,
+// names are matched case-insensitively by ValueOfIgnoreCase.
+type EnumFlag[T EnumDefinition] struct {
+	value *T
+}
+
+// NewEnumFlag Create an EnumFlag that stores the parsed enumeration into p. The current value of *p is the default value.
+func NewEnumFlag[T EnumDefinition](p *T) *EnumFlag[T] {
+	return &EnumFlag[T]{value: p}
+}
+
+func (f *EnumFlag[T]) String() string {
+	if f == nil || f.value == nil || isZeroEnum(*f.value) {
+		return ""
+	}
+	return (*f.value).Name()
+}
+
+func (f *EnumFlag[T]) Set(s string) error {
+	e, valid := ValueOfIgnoreCase[T](strings.TrimSpace(s))
+	if !valid {
+		return errors.New("invalid value " + strconv.Quote(s) + ", allowed: " + strings.Join(EnumNames[T](), ", "))
+	}
+	*f.value = e
+	return nil
+}
+
+// Get Implement flag.Getter, return the current enumeration instance
+func (f *EnumFlag[T]) Get() any {
+	return *f.value
+}
+
+func (f *EnumFlag[T]) Complete(prefix string) []string {
+	return completeNames(EnumNames[T](), prefix)
+}
+
+// EnumSetFlag Bind an EnumSet to the standard flag package. The flag value is a comma-separated list of names,
+// such as --perms=AddLabels,AddTopic. Repeating the flag accumulates members,
+// and the first occurrence replaces the default members of the set.
+type EnumSetFlag[E EnumDefinition] struct {
+	set     EnumSet[E]
+	changed bool
+}
+
+// NewEnumSetFlag Create an EnumSetFlag that stores the parsed enumerations into set.
+// The members already present in set are the default value.
+func NewEnumSetFlag[E EnumDefinition](set EnumSet[E]) *EnumSetFlag[E] {
+	return &EnumSetFlag[E]{set: set}
+}
+
+func (f *EnumSetFlag[E]) String() string {
+	if f == nil || f.set == nil {
+		return ""
+	}
+	return strings.Join(f.set.Names(), ",")
+}
+
+func (f *EnumSetFlag[E]) Set(s string) error {
+	var enums []E
+	var invalid []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e, valid := ValueOfIgnoreCase[E](name)
+		if !valid {
+			invalid = append(invalid, strconv.Quote(name))
+			continue
+		}
+		enums = append(enums, e)
+	}
+	if len(invalid) > 0 {
+		return errors.New("invalid value " + strings.Join(invalid, ", ") + ", allowed: " + strings.Join(EnumNames[E](), ", "))
+	}
+	if !f.changed {
+		f.set.Clear()
+		f.changed = true
+	}
+	for _, e := range enums {
+		f.set.Add(e)
+	}
+	return nil
+}
+
+// Get Implement flag.Getter, return the bound EnumSet
+func (f *EnumSetFlag[E]) Get() any {
+	return f.set
+}
+
+// Complete Complete the last comma-separated name of prefix, the names already in prefix are not proposed again
+func (f *EnumSetFlag[E]) Complete(prefix string) []string {
+	head, last := "", prefix
+	if i := strings.LastIndex(prefix, ","); i >= 0 {
+		head, last = prefix[:i+1], prefix[i+1:]
+	}
+	chosen := make(map[string]bool)
+	for _, name := range strings.Split(head, ",") {
+		chosen[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	var res []string
+	for _, name := range completeNames(EnumNames[E](), last) {
+		if !chosen[strings.ToLower(name)] {
+			res = append(res, head+name)
+		}
+	}
+	return res
+}
+
+// FlagVar Define an enumeration flag with specified name, default value, and usage string.
+// The argument p points to a T variable in which to store the value of the flag.
+// The allowed names are appended to the usage string.
+func FlagVar[T EnumDefinition](fs *flag.FlagSet, p *T, name string, value T, usage string) {
+	*p = value
+	fs.Var(NewEnumFlag(p), name, FlagUsage[T](usage))
+}
+
+// Flag Define an enumeration flag with specified name, default value, and usage string.
+// The return value is the address of a T variable that stores the value of the flag.
+func Flag[T EnumDefinition](fs *flag.FlagSet, name string, value T, usage string) *T {
+	p := new(T)
+	FlagVar(fs, p, name, value, usage)
+	return p
+}
+
+// FlagSetVar Define an EnumSet flag with specified name and usage string, parsed members are stored into set.
+func FlagSetVar[E EnumDefinition](fs *flag.FlagSet, set EnumSet[E], name string, usage string) {
+	fs.Var(NewEnumSetFlag(set), name, FlagUsage[E](usage))
+}
+
+// FlagUsage Append the allowed names of the enumeration type to the usage string
+func FlagUsage[T EnumDefinition](usage string) string {
+	allowed := "(one of: " + strings.Join(EnumNames[T](), ", ") + ")"
+	if usage == "" {
+		return allowed
+	}
+	return usage + " " + allowed
+}
+
+// CompleteFlag Return the shell-completion candidates of arg, the argument currently being typed on the command line.
+// arg is in the form of -name=prefix or --name=prefix, only flags whose value implements Completer are completed.
+func CompleteFlag(fs *flag.FlagSet, arg string) []string {
+	if !strings.HasPrefix(arg, "-") {
+		return nil
+	}
+	name, prefix, found := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	if !found {
+		return nil
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return nil
+	}
+	completer, ok := f.Value.(Completer)
+	if !ok {
+		return nil
+	}
+	var res []string
+	for _, candidate := range completer.Complete(prefix) {
+		res = append(res, arg[:len(arg)-len(prefix)]+candidate)
+	}
+	return res
+}
+
+func completeNames(names []string, prefix string) (res []string) {
+	for _, name := range names {
+		if hasPrefixFold(name, prefix) {
+			res = append(res, name)
+		}
+	}
+	return
+}
+
+// hasPrefixFold Whether s starts with prefix, ignoring case like strings.EqualFold.
+// Runes are compared one by one, as the other case of a rune may be encoded in a different number of bytes
+func hasPrefixFold(s, prefix string) bool {
+	for _, p := range prefix {
+		r, size := utf8.DecodeRuneInString(s)
+		if size == 0 || !strings.EqualFold(string(r), string(p)) {
+			return false
+		}
+		s = s[size:]
+	}
+	return true
+}
+
+// isZeroEnum Whether e is the zero value of its type, such as a nil pointer enumeration
+func isZeroEnum[T EnumDefinition](e T) bool {
+	return reflect.ValueOf(&e).Elem().IsZero()
+}
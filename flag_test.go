@@ -0,0 +1,18 @@
+package goenum
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCompleteNames(t *testing.T) {
+	names := []string{"Kelvin", "Stop", "状态", "状况"}
+	require.Equal(t, []string{"Stop"}, completeNames(names, "st"))
+	// 大小写形式的字节数不同：ſ(2字节)与S，K(开尔文符号，3字节)与K
+	require.Equal(t, []string{"Stop"}, completeNames(names, "ſ"))
+	require.Equal(t, []string{"Kelvin"}, completeNames(names, "K"))
+	require.Equal(t, []string{"状态", "状况"}, completeNames(names, "状"))
+	require.Equal(t, []string{"状态"}, completeNames(names, "状态"))
+	require.Nil(t, completeNames(names, "状态x"))
+	require.Len(t, completeNames(names, ""), 4)
+}
@@ -0,0 +1,100 @@
+package goenum
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format Implement fmt.Formatter for both goenum.Enum and *goenum.Enum embedding:
+//   - %s, %v The name, such as Paid
+//   - %q The quoted name, such as "Paid"
+//   - %d The ordinal, such as 2
+//   - %+v The type, name and ordinal, such as internal.TradeState(Paid#2)
+//   - %#v An expression evaluating to the instance, such as goenum.MustValueOf[internal.TradeState]("Paid")
+//
+// Width and flags such as %-10s and %03d are honored. Format is promoted to the embedding types and can not see
+// their own String method, such types keep it by implementing Format with Formatted
+func (e Enum) Format(f fmt.State, verb rune) {
+	formatEnum(f, verb, e)
+}
+
+// Formatted Wrap e into a fmt.Formatter printing e.String() for %s, %v and %q, and the other verbs like Enum.Format.
+// A type embedding goenum.Enum with its own String method can forward its Format to it:
+//
+//	func (c StatusCode) Format(f fmt.State, verb rune) { goenum.Formatted(c).Format(f, verb) }
+func Formatted(e EnumDefinition) fmt.Formatter {
+	return enumFormatter{e: e}
+}
+
+type enumFormatter struct {
+	e EnumDefinition
+}
+
+func (ef enumFormatter) Format(f fmt.State, verb rune) {
+	formatEnum(f, verb, ef.e)
+}
+
+func formatEnum(f fmt.State, verb rune, e EnumDefinition) {
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			_, _ = fmt.Fprint(f, goSyntax(e))
+		case f.Flag('+'):
+			_, _ = fmt.Fprintf(f, directive(f, 's'), e.Type()+"("+e.Name()+"#"+strconv.Itoa(e.Ordinal())+")")
+		default:
+			_, _ = fmt.Fprintf(f, directive(f, 's'), e.String())
+		}
+	case 's', 'q':
+		_, _ = fmt.Fprintf(f, directive(f, verb), e.String())
+	case 'd':
+		_, _ = fmt.Fprintf(f, directive(f, 'd'), e.Ordinal())
+	default:
+		_, _ = fmt.Fprintf(f, "%%!%c(%s=%s)", verb, e.Type(), e.Name())
+	}
+}
+
+// goSyntax The %#v of e. Members of the default registry are printed as the MustValueOf expression evaluating to them,
+// the others, such as members of a custom registry, as the Go syntax of their Enum
+func goSyntax(e EnumDefinition) string {
+	if e.Type() == "" {
+		return "goenum.Enum{}"
+	}
+	if m := loadRegistry().find(e.Type(), e.Name()); m != nil && m.Ordinal() == e.Ordinal() {
+		return "goenum.MustValueOf[" + e.Type() + "](" + strconv.Quote(e.Name()) + ")"
+	}
+	return fmt.Sprintf("goenum.Enum{name:%q, _type:%q, index:%d}", e.Name(), e.Type(), e.Ordinal())
+}
+
+// Format Implement fmt.Formatter, each member is formatted by the same verb, width and flags as Formatted,
+// like fmt formats the elements of a slice, such as %d for [0,17] and %-8s for [Created ,Shipped ]
+func (set *UnsafeEnumSet[E]) Format(f fmt.State, verb rune) {
+	member := formatString(f, verb)
+	var parts []string
+	set.Each(func(e E) bool {
+		parts = append(parts, fmt.Sprintf(member, Formatted(e)))
+		return true
+	})
+	_, _ = fmt.Fprint(f, "["+strings.Join(parts, ",")+"]")
+}
+
+// directive Rebuild the formatting directive of verb with the width, precision and flags of f, except + and #
+func directive(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "- 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
@@ -0,0 +1,29 @@
+//go:build !go1.20
+
+package goenum
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// formatString The formatting directive of verb with all the flags, width and precision of f, like fmt.FormatString
+func formatString(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
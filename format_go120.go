@@ -0,0 +1,10 @@
+//go:build go1.20
+
+package goenum
+
+import "fmt"
+
+// formatString The formatting directive of verb with all the flags, width and precision of f
+func formatString(f fmt.State, verb rune) string {
+	return fmt.FormatString(f, verb)
+}
@@ -0,0 +1,220 @@
+// Package goenumtest Assertions and utilities for testing enumerations:
+//
+//	func TestTradeState(t *testing.T) {
+//		goenumtest.AssertRoundTrip[TradeState](t)
+//		goenumtest.AssertExhaustive(t, func(s TradeState) { _ = s.Label() })
+//		goenumtest.AssertNoDuplicateAttrs[ErrorCode](t, "code")
+//	}
+//
+// Sandbox registers temporary enumerations and removes them when the test finishes.
+package goenumtest
+
+import (
+	"fmt"
+	"github.com/lvyahui8/goenum"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var (
+	bytesType = reflect.TypeOf([]byte(nil))
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// codec A pair of methods MarshalXxx() ([]byte, error) and UnmarshalXxx([]byte) error, such as MarshalJSON and UnmarshalJSON
+type codec struct {
+	name      string
+	marshal   string
+	unmarshal string
+}
+
+// codecsOf Find the codecs of T. The marshal methods are looked up on T, and the unmarshal methods on *T,
+// or on T itself if T is a pointer type like *ColorEnum
+func codecsOf(t reflect.Type) (codecs []codec) {
+	target := decodeTarget(t)
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		suffix := strings.TrimPrefix(m.Name, "Marshal")
+		if suffix == m.Name || suffix == "" || !isMarshal(m.Type) {
+			continue
+		}
+		um, ok := target.MethodByName("Unmarshal" + suffix)
+		if !ok || !isUnmarshal(um.Type) {
+			continue
+		}
+		codecs = append(codecs, codec{name: suffix, marshal: m.Name, unmarshal: um.Name})
+	}
+	return
+}
+
+// isMarshal func(receiver) ([]byte, error)
+func isMarshal(mt reflect.Type) bool {
+	return mt.NumIn() == 1 && mt.NumOut() == 2 && mt.Out(0) == bytesType && mt.Out(1) == errorType
+}
+
+// isUnmarshal func(receiver, []byte) error
+func isUnmarshal(mt reflect.Type) bool {
+	return mt.NumIn() == 2 && mt.In(1) == bytesType && mt.NumOut() == 1 && mt.Out(0) == errorType
+}
+
+func decodeTarget(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t
+	}
+	return reflect.PtrTo(t)
+}
+
+// AssertRoundTrip Assert that every member of T survives each encoding T implements, such as JSON, text and binary.
+// An encoding is a pair of methods MarshalXxx() ([]byte, error) on T and UnmarshalXxx([]byte) error on *T,
+// encodings implemented on one side only are not checked. It fails if T implements no encoding at all
+func AssertRoundTrip[T goenum.EnumDefinition](t testing.TB) bool {
+	t.Helper()
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	codecs := codecsOf(typ)
+	if len(codecs) == 0 {
+		t.Errorf("%s implements no Marshaler/Unmarshaler pair", typ)
+		return false
+	}
+	ok := true
+	for _, e := range goenum.Values[T]() {
+		for _, c := range codecs {
+			if err := roundTrip(e, typ, c); err != nil {
+				t.Errorf("%s %s: %s", typ, c.name, err)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+func roundTrip[T goenum.EnumDefinition](e T, typ reflect.Type, c codec) error {
+	out := reflect.ValueOf(e).MethodByName(c.marshal).Call(nil)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return fmt.Errorf("%s %s: %w", c.marshal, e.Name(), err)
+	}
+	target := reflect.New(typ)
+	if typ.Kind() == reflect.Ptr {
+		// *ColorEnum 的方法定义在指针上，直接解码到新的实例
+		target = reflect.New(typ.Elem())
+	}
+	in := target.MethodByName(c.unmarshal).Call([]reflect.Value{out[0]})
+	if err, _ := in[0].Interface().(error); err != nil {
+		return fmt.Errorf("%s %q: %w", c.unmarshal, out[0].Bytes(), err)
+	}
+	if typ.Kind() != reflect.Ptr {
+		target = target.Elem()
+	}
+	decoded := target.Interface().(T)
+	if !decoded.Equals(e) || decoded.Ordinal() != e.Ordinal() {
+		return fmt.Errorf("%s is decoded from %q as %+v", e.Name(), out[0].Bytes(), goenum.Formatted(decoded))
+	}
+	return nil
+}
+
+// AssertExhaustive Assert that f handles every member of T without panicking, such as a switch with a panicking default
+func AssertExhaustive[T goenum.EnumDefinition](t testing.TB, f func(e T)) bool {
+	t.Helper()
+	ok := true
+	for _, e := range goenum.Values[T]() {
+		if r := call(f, e); r != nil {
+			t.Errorf("%s: %s is not handled: %v", e.Type(), e.Name(), r)
+			ok = false
+		}
+	}
+	return ok
+}
+
+func call[T goenum.EnumDefinition](f func(e T), e T) (recovered any) {
+	defer func() {
+		recovered = recover()
+	}()
+	f(e)
+	return
+}
+
+// AssertNoDuplicateAttrs Assert that the members of T have distinct values of each attribute, such as an error code.
+// At least one attribute name is required, see goenum.Attributes for the attribute names
+func AssertNoDuplicateAttrs[T goenum.EnumDefinition](t testing.TB, attributes ...string) bool {
+	t.Helper()
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if len(attributes) == 0 {
+		t.Errorf("%s: no attribute to check", typ)
+		return false
+	}
+	ok := true
+	for _, attr := range attributes {
+		// 属性值 -> 成员名称，按首次出现的顺序报告
+		owners := make(map[string][]string)
+		var values []string
+		found := false
+		for _, e := range goenum.Values[T]() {
+			for _, a := range goenum.Attributes(e) {
+				if a.Name != attr {
+					continue
+				}
+				found = true
+				v := fmt.Sprint(a.Value)
+				if _, exist := owners[v]; !exist {
+					values = append(values, v)
+				}
+				owners[v] = append(owners[v], e.Name())
+			}
+		}
+		if !found {
+			t.Errorf("%s has no attribute %s", typ, attr)
+			ok = false
+			continue
+		}
+		for _, v := range values {
+			if len(owners[v]) > 1 {
+				t.Errorf("%s: attribute %s = %s is shared by %s", typ, attr, v, strings.Join(owners[v], ", "))
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// Sandbox Register temporary enumerations to the default registry in a test. The members registered by NewEnum
+// are removed when the test finishes, so the test can be run repeatedly. Tests using Sandbox must not run in parallel
+// with other tests registering enumerations: the test fails if the default registry has been changed otherwise,
+// instead of dropping those changes
+type Sandbox struct {
+	t          testing.TB
+	checkpoint *goenum.Checkpoint
+}
+
+// NewSandbox Create a sandbox restored by t.Cleanup
+func NewSandbox(t testing.TB) *Sandbox {
+	t.Helper()
+	s := &Sandbox{t: t, checkpoint: goenum.DefaultRegistry().Checkpoint()}
+	t.Cleanup(func() {
+		if err := s.checkpoint.Restore(); err != nil {
+			t.Errorf("sandbox: %v", err)
+		}
+	})
+	return s
+}
+
+// NewEnum Like goenum.NewEnum, but return the panic as an error, such as for a duplicate name
+func NewEnum[T goenum.EnumDefinition](s *Sandbox, name string, src ...T) (t T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	t = goenum.NewEnum[T](name, src...)
+	s.checkpoint.Accept()
+	return t, nil
+}
+
+// MustNewEnum Like goenum.NewEnum, fail the test instead of panicking
+func MustNewEnum[T goenum.EnumDefinition](s *Sandbox, name string, src ...T) T {
+	s.t.Helper()
+	e, err := NewEnum[T](s, name, src...)
+	if err != nil {
+		s.t.Fatalf("NewEnum %s: %v", name, err)
+	}
+	return e
+}
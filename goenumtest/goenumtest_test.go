@@ -0,0 +1,131 @@
+package goenumtest
+
+import (
+	"fmt"
+	"github.com/lvyahui8/goenum"
+	"github.com/lvyahui8/goenum/internal"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// recorder 记录断言失败的信息，而不是让测试失败
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	require.True(t, AssertRoundTrip[internal.Role](t))
+	r := &recorder{TB: t}
+	require.False(t, AssertRoundTrip[internal.TradeState](r))
+	require.Equal(t, []string{"internal.TradeState implements no Marshaler/Unmarshaler pair"}, r.errors)
+}
+
+// Level JSON与文本编码都可以往返，Broken的文本编码错误
+type Level struct {
+	goenum.Enum
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	if l.Name() == "Broken" {
+		return []byte("Low"), nil
+	}
+	return l.Enum.MarshalText()
+}
+
+func (l *Level) UnmarshalText(data []byte) (err error) {
+	level, valid := goenum.ValueOf[Level](string(data))
+	if !valid {
+		return fmt.Errorf("unknown level %s", data)
+	}
+	*l = level
+	return nil
+}
+
+func (l *Level) UnmarshalJSON(data []byte) (err error) {
+	*l, err = goenum.Unmarshal[Level](data)
+	return
+}
+
+func TestAssertRoundTrip_Codecs(t *testing.T) {
+	s := NewSandbox(t)
+	MustNewEnum[Level](s, "Low")
+	MustNewEnum[Level](s, "High")
+	require.True(t, AssertRoundTrip[Level](t))
+	MustNewEnum[Level](s, "Broken")
+	r := &recorder{TB: t}
+	require.False(t, AssertRoundTrip[Level](r))
+	require.Equal(t, []string{`goenumtest.Level Text: Broken is decoded from "Low" as goenumtest.Level(Low#0)`}, r.errors)
+}
+
+func TestAssertExhaustive(t *testing.T) {
+	label := func(s internal.TradeState) {
+		switch s {
+		case internal.TradeCreated, internal.TradePaid, internal.TradeShipped, internal.TradeDelivered:
+		default:
+			panic("unexpected state " + s.Name())
+		}
+	}
+	r := &recorder{TB: t}
+	require.False(t, AssertExhaustive(r, label))
+	require.Equal(t, []string{"internal.TradeState: Failed is not handled: unexpected state Failed"}, r.errors)
+	require.True(t, AssertExhaustive(t, func(s internal.TradeState) { _ = s.IsFinal() }))
+}
+
+func TestAssertNoDuplicateAttrs(t *testing.T) {
+	require.True(t, AssertNoDuplicateAttrs[internal.ErrorCode](t, "desc"))
+	r := &recorder{TB: t}
+	require.False(t, AssertNoDuplicateAttrs[internal.ErrorCode](r, "code", "desc", "priority"))
+	require.Equal(t, []string{
+		"internal.Code: attribute code = 2 is shared by Member, Trade, Delivery",
+		"internal.Code has no attribute priority",
+	}, r.errors)
+	r = &recorder{TB: t}
+	require.False(t, AssertNoDuplicateAttrs[internal.ErrorCode](r))
+	require.Equal(t, []string{"internal.Code: no attribute to check"}, r.errors)
+}
+
+func TestSandbox(t *testing.T) {
+	before := goenum.Size[internal.Role]()
+	t.Run("Register", func(t *testing.T) {
+		s := NewSandbox(t)
+		guest := MustNewEnum[internal.Role](s, "Guest")
+		require.Equal(t, before, guest.Ordinal())
+		_, valid := goenum.ValueOf[internal.Role]("Guest")
+		require.True(t, valid)
+		_, err := NewEnum[internal.Role](s, "Owner")
+		require.EqualError(t, err, "Enum must be unique")
+	})
+	// 子测试结束后临时注册的成员被移除，可以再次注册
+	require.Equal(t, before, goenum.Size[internal.Role]())
+	_, valid := goenum.ValueOf[internal.Role]("Guest")
+	require.False(t, valid)
+	t.Run("Again", func(t *testing.T) {
+		guest := MustNewEnum[internal.Role](NewSandbox(t), "Guest")
+		require.Equal(t, before, guest.Ordinal())
+	})
+}
+
+// stray 绕过沙箱注册的类型
+type stray struct {
+	goenum.Enum
+}
+
+func TestSandbox_Changed(t *testing.T) {
+	r := &recorder{TB: t}
+	t.Run("Register", func(t *testing.T) {
+		r.TB = t
+		s := NewSandbox(r)
+		MustNewEnum[stray](s, "A")
+		goenum.NewEnum[stray]("B")
+	})
+	require.Equal(t, []string{"sandbox: goenum: the registry has been changed by others since the checkpoint, it is not restored"}, r.errors)
+	// 沙箱之外的修改被保留
+	require.Equal(t, 2, goenum.Size[stray]())
+}
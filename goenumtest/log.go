@@ -0,0 +1,11 @@
+//go:build go1.21
+
+package goenumtest
+
+import "github.com/lvyahui8/goenum"
+
+// MarkSensitive Mark T sensitive like goenum.MarkSensitive, the mark is removed after the test of the sandbox
+func MarkSensitive[T goenum.EnumDefinition](s *Sandbox, attributes ...string) {
+	goenum.MarkSensitive[T](attributes...)
+	s.t.Cleanup(goenum.UnmarkSensitive[T])
+}
@@ -0,0 +1,19 @@
+//go:build go1.21
+
+package goenumtest
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/lvyahui8/goenum/internal"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestMarkSensitive(t *testing.T) {
+	t.Run("Mark", func(t *testing.T) {
+		MarkSensitive[internal.Role](NewSandbox(t))
+		require.Equal(t, goenum.Redacted, internal.Owner.LogValue().String())
+	})
+	require.Equal(t, slog.KindGroup, internal.Owner.LogValue().Kind())
+}
@@ -0,0 +1,178 @@
+// Package httpbind Bind the query, header, path and form values of an HTTP request to the
+// enumeration, EnumSet and basic fields of a struct.
+package httpbind
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Sources and the struct tags naming the keys of the fields
+const (
+	Query  = "query"
+	Header = "header"
+	Path   = "path"
+	Form   = "form"
+)
+
+// PathValues Return the value of the path parameter, such as chi.URLParam or the PathValue method of http.Request
+type PathValues func(r *http.Request, name string) string
+
+type options struct {
+	pathValues PathValues
+}
+
+// Option Option of Bind
+type Option func(o *options)
+
+// WithPathValues Specify how to get the path parameters, by default http.Request.PathValue is used when built with Go 1.22 or later
+func WithPathValues(f PathValues) Option {
+	return func(o *options) {
+		o.pathValues = f
+	}
+}
+
+// FieldError An invalid request value
+type FieldError struct {
+	// Source Where the value comes from: query, header, path or form
+	Source string `json:"source"`
+	// Name The name of the parameter
+	Name string `json:"name"`
+	// Field Path of the struct field
+	Field string `json:"field"`
+	Value string `json:"value"`
+	// Allowed The allowed enumeration names, empty if the field is not an enumeration or EnumSet
+	Allowed []string `json:"allowed,omitempty"`
+	Message string   `json:"message"`
+}
+
+// Error The request is invalid and should be answered with 400 Bad Request, all invalid values are listed
+type Error struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// StatusCode Always http.StatusBadRequest
+func (e *Error) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// Bind Decode the request into the struct pointed to by dst. Fields are tagged with the name of the parameter
+// in their source, such as `query:"state"`, `header:"X-State"`, `path:"id"` and `form:"perm"`.
+// Enumerations are matched case-insensitively, EnumSet and slice fields accept both comma-separated and repeated parameters,
+// and `default:"Paid"` gives the value of missing parameters. See goenum.Bind for the supported field types.
+// Invalid values, and a malformed body for form fields, are reported together by an *Error
+func Bind(r *http.Request, dst any, opts ...Option) error {
+	o := &options{pathValues: defaultPathValues}
+	for _, opt := range opts {
+		opt(o)
+	}
+	// formErr 表单只在有form字段时解析，解析失败后不再重试
+	var formErr error
+	sources := []struct {
+		tag    string
+		lookup goenum.Lookup
+	}{
+		{Path, func(key string) ([]string, bool) {
+			if o.pathValues == nil {
+				return nil, false
+			}
+			v := o.pathValues(r, key)
+			return []string{v}, v != ""
+		}},
+		{Query, valuesLookup(r.URL.Query())},
+		{Header, func(key string) ([]string, bool) {
+			values := r.Header.Values(key)
+			return values, len(values) > 0
+		}},
+		{Form, func(key string) ([]string, bool) {
+			if formErr == nil {
+				formErr = parseForm(r)
+			}
+			if formErr != nil {
+				return nil, false
+			}
+			return valuesLookup(r.PostForm)(key)
+		}},
+	}
+	res := &Error{}
+	for _, source := range sources {
+		err := goenum.Bind(dst, source.tag, source.lookup)
+		if err == nil {
+			continue
+		}
+		var bindErr *goenum.BindError
+		if !errors.As(err, &bindErr) {
+			return err
+		}
+		for _, fe := range bindErr.Errors {
+			res.Errors = append(res.Errors, FieldError{
+				Source:  source.tag,
+				Name:    fe.Key,
+				Field:   fe.Field,
+				Value:   fe.Value,
+				Allowed: fe.Allowed,
+				Message: message(source.tag, fe),
+			})
+		}
+	}
+	if formErr != nil {
+		res.Errors = append(res.Errors, FieldError{Source: Form, Message: "invalid form body: " + formErr.Error()})
+	}
+	if len(res.Errors) > 0 {
+		return res
+	}
+	return nil
+}
+
+// WriteError Write err as a JSON response, *Error is answered with 400 Bad Request, and other errors with 500
+func WriteError(w http.ResponseWriter, err error) {
+	var bindErr *Error
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if errors.As(err, &bindErr) {
+		w.WriteHeader(bindErr.StatusCode())
+		_ = json.NewEncoder(w).Encode(bindErr)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": err.Error()})
+}
+
+func message(source string, fe *goenum.FieldError) string {
+	msg := "invalid " + source + " parameter " + fe.Key + ": " + strconv.Quote(fe.Value)
+	if len(fe.Allowed) > 0 {
+		return msg + ", allowed: " + strings.Join(fe.Allowed, ", ")
+	}
+	if fe.Err != nil {
+		msg += ", " + fe.Err.Error()
+	}
+	return msg
+}
+
+func valuesLookup(values map[string][]string) goenum.Lookup {
+	return func(key string) ([]string, bool) {
+		v, ok := values[key]
+		return v, ok && len(v) > 0
+	}
+}
+
+func parseForm(r *http.Request) error {
+	if r.PostForm != nil {
+		return nil
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.ParseMultipartForm(32 << 20)
+	}
+	return r.ParseForm()
+}
@@ -0,0 +1,27 @@
+//go:build go1.22
+
+package httpbind
+
+import (
+	"github.com/lvyahui8/goenum/internal"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestBindPathValue 模拟http.ServeMux匹配 /orders/{color} 后设置的路径参数
+func TestBindPathValue(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/orders/yellow", nil)
+	r.SetPathValue("color", "yellow")
+	var req ListRequest
+	require.Nil(t, Bind(r, &req))
+	require.True(t, req.Color.Equals(internal.Yellow))
+
+	r = httptest.NewRequest(http.MethodGet, "/orders/blue", nil)
+	r.SetPathValue("color", "blue")
+	w := httptest.NewRecorder()
+	WriteError(w, Bind(r, &req))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"allowed":["Red","Yellow"]`)
+}
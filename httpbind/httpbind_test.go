@@ -0,0 +1,95 @@
+package httpbind
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/lvyahui8/goenum/internal"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type ListRequest struct {
+	State   internal.TradeState                        `query:"state" default:"Created"`
+	Perms   *goenum.UnsafeEnumSet[internal.Permission] `query:"perm"`
+	Reverse []internal.ReverseState                    `header:"X-Reverse-State"`
+	Color   *internal.ColorEnum                        `path:"color"`
+	Page    int                                        `query:"page" default:"1"`
+	Code    internal.ErrorCode                         `form:"code"`
+}
+
+func pathValues(values map[string]string) Option {
+	return WithPathValues(func(r *http.Request, name string) string {
+		return values[name]
+	})
+}
+
+func TestBind(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/orders/red?state=paid&perm=AddLabels&perm=AddTopic,ViewMergeRequest",
+			strings.NewReader(url.Values{"code": {"NetworkError"}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Add("X-Reverse-State", "Created,Failed")
+		r.Header.Add("X-Reverse-State", "refunded")
+		var req ListRequest
+		require.Nil(t, Bind(r, &req, pathValues(map[string]string{"color": "red"})))
+		require.True(t, req.State.Equals(internal.TradePaid))
+		require.Equal(t, "[AddLabels,AddTopic,ViewMergeRequest]", req.Perms.String())
+		require.Equal(t, []internal.ReverseState{internal.ReverseCreated, internal.ReverseFailed, internal.ReverseRefunded}, req.Reverse)
+		require.True(t, req.Color.Equals(internal.Red))
+		require.Equal(t, 1, req.Page)
+		require.True(t, req.Code.Equals(internal.NetworkError))
+	})
+	t.Run("Default", func(t *testing.T) {
+		var req ListRequest
+		require.Nil(t, Bind(httptest.NewRequest(http.MethodGet, "/orders", nil), &req, WithPathValues(nil)))
+		require.True(t, req.State.Equals(internal.TradeCreated))
+		require.Nil(t, req.Perms)
+		require.Nil(t, req.Color)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/orders/blue?state=Unknown&perm=AddLabels&perm=Foo&page=x", nil)
+		r.Header.Set("X-Reverse-State", "Bar")
+		var req ListRequest
+		err := Bind(r, &req, pathValues(map[string]string{"color": "blue"}))
+		var bindErr *Error
+		require.True(t, errors.As(err, &bindErr))
+		require.Equal(t, http.StatusBadRequest, bindErr.StatusCode())
+		require.Len(t, bindErr.Errors, 5)
+		require.Equal(t, FieldError{Source: Path, Name: "color", Field: "ListRequest.Color", Value: "blue",
+			Allowed: []string{"Red", "Yellow"}, Message: `invalid path parameter color: "blue", allowed: Red, Yellow`}, bindErr.Errors[0])
+		require.Equal(t, "state", bindErr.Errors[1].Name)
+		require.Equal(t, "Foo", bindErr.Errors[2].Value)
+		require.Equal(t, "page", bindErr.Errors[3].Name)
+		require.Equal(t, Header, bindErr.Errors[4].Source)
+
+		w := httptest.NewRecorder()
+		WriteError(w, err)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var body Error
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Equal(t, *bindErr, body)
+	})
+	t.Run("MalformedForm", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("code=%zz"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var req ListRequest
+		err := Bind(r, &req, WithPathValues(nil))
+		var bindErr *Error
+		require.True(t, errors.As(err, &bindErr))
+		require.Len(t, bindErr.Errors, 1)
+		require.Equal(t, Form, bindErr.Errors[0].Source)
+		require.Contains(t, bindErr.Errors[0].Message, "invalid form body: ")
+	})
+	t.Run("InternalError", func(t *testing.T) {
+		err := Bind(httptest.NewRequest(http.MethodGet, "/", nil), ListRequest{})
+		require.NotNil(t, err)
+		w := httptest.NewRecorder()
+		WriteError(w, err)
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,6 @@
+//go:build !go1.22
+
+package httpbind
+
+// defaultPathValues http.Request.PathValue is not available before Go 1.22
+var defaultPathValues PathValues
@@ -0,0 +1,10 @@
+//go:build go1.22
+
+package httpbind
+
+import "net/http"
+
+// defaultPathValues Use the path parameters matched by http.ServeMux
+var defaultPathValues PathValues = func(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
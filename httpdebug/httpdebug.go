@@ -0,0 +1,235 @@
+// Package httpdebug Serve the registered enumeration types and members over HTTP, as HTML pages and JSON,
+// like expvar and net/http/pprof:
+//
+//	httpdebug.Register(http.DefaultServeMux)
+//
+// The index page /debug/enums lists the types and supports searching by type or member name with ?q=,
+// the detail page /debug/enums/types/{type} lists the members of a type. Add ?format=json, or request with
+// the Accept: application/json header, to get JSON.
+package httpdebug
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/lvyahui8/goenum"
+	"html/template"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Path The path where Register mounts the handler
+const Path = "/debug/enums"
+
+// TypeSummary A type listed on the index page
+type TypeSummary struct {
+	Name    string `json:"name"`
+	PkgPath string `json:"pkgPath"`
+	Pointer bool   `json:"pointer"`
+	Size    int    `json:"size"`
+	// Members Names of the members, only the matched members if searching
+	Members []string `json:"members"`
+}
+
+// Type The detail of a type
+type Type struct {
+	Name    string   `json:"name"`
+	PkgPath string   `json:"pkgPath"`
+	Pointer bool     `json:"pointer"`
+	Size    int      `json:"size"`
+	Members []Member `json:"members"`
+}
+
+// Member The detail of a member
+type Member struct {
+	goenum.MemberDescriptor
+	// Fields Exported struct fields of the member, fields of embedded structs are flattened
+	Fields []Field `json:"fields,omitempty"`
+	// Word, Bit The position of the member in EnumSet: bit Bit of the Word-th uint64
+	Word int `json:"word"`
+	Bit  int `json:"bit"`
+}
+
+// Field An exported struct field, the value is formatted by fmt
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type handler struct {
+	reg *goenum.Registry
+}
+
+// Handler Serve the types of the registry reg, nil for the default registry.
+// The pages are resolved relative to the last /debug/enums of the request path, so the handler can be mounted
+// under any prefix ending with /debug/enums, such as /tenants/a/debug/enums
+func Handler(reg *goenum.Registry) http.Handler {
+	if reg == nil {
+		reg = goenum.DefaultRegistry()
+	}
+	return &handler{reg: reg}
+}
+
+// Register Mount the handler of the default registry at /debug/enums of mux
+func Register(mux *http.ServeMux) {
+	h := Handler(nil)
+	mux.Handle(Path, h)
+	mux.Handle(Path+"/", h)
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	base, rest := "", r.URL.Path
+	if i := strings.LastIndex(r.URL.Path, Path); i >= 0 {
+		base, rest = r.URL.Path[:i+len(Path)], r.URL.Path[i+len(Path):]
+	}
+	rest = strings.Trim(rest, "/")
+	asJSON := r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+	switch {
+	case rest == "":
+		summaries := search(h.reg.Types(), r.URL.Query().Get("q"))
+		if asJSON {
+			writeJSON(w, summaries)
+			return
+		}
+		render(w, indexTemplate, map[string]any{"Base": base, "Query": r.URL.Query().Get("q"), "Types": summaries})
+	case strings.HasPrefix(rest, "types/"):
+		name, err := url.PathUnescape(strings.TrimPrefix(rest, "types/"))
+		t, ok := h.describe(name)
+		if err != nil || !ok {
+			http.Error(w, "enum type not found", http.StatusNotFound)
+			return
+		}
+		if asJSON {
+			writeJSON(w, t)
+			return
+		}
+		render(w, typeTemplate, map[string]any{"Base": base, "Type": t})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// search Filter the types by the case-insensitive substring of type or member names
+func search(types []goenum.TypeDescriptor, q string) []TypeSummary {
+	q = strings.ToLower(strings.TrimSpace(q))
+	res := make([]TypeSummary, 0, len(types))
+	for _, d := range types {
+		typeMatched := strings.Contains(strings.ToLower(d.Name), q)
+		var members []string
+		for _, m := range d.Members {
+			if typeMatched || strings.Contains(strings.ToLower(m.Name), q) {
+				members = append(members, m.Name)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		res = append(res, TypeSummary{Name: d.Name, PkgPath: d.PkgPath, Pointer: d.Pointer, Size: d.Size, Members: members})
+	}
+	return res
+}
+
+func (h *handler) describe(name string) (Type, bool) {
+	for _, d := range h.reg.Types() {
+		if d.Name != name {
+			continue
+		}
+		t := Type{Name: d.Name, PkgPath: d.PkgPath, Pointer: d.Pointer, Size: d.Size}
+		for _, m := range d.Members {
+			t.Members = append(t.Members, Member{
+				MemberDescriptor: m,
+				Fields:           fields(reflect.ValueOf(m.Value)),
+				Word:             m.Ordinal >> 6,
+				Bit:              m.Ordinal & 63,
+			})
+		}
+		return t, true
+	}
+	return Type{}, false
+}
+
+var enumType = reflect.TypeOf(goenum.Enum{})
+
+// fields Exported fields of the struct v, embedded structs are flattened and goenum.Enum is skipped
+func fields(v reflect.Value) (res []Field) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Type == enumType || (sf.Type.Kind() == reflect.Ptr && sf.Type.Elem() == enumType) {
+			continue
+		}
+		if sf.Anonymous {
+			res = append(res, fields(v.Field(i))...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		res = append(res, Field{Name: sf.Name, Value: fmt.Sprint(v.Field(i).Interface())})
+	}
+	return
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func render(w http.ResponseWriter, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var funcs = template.FuncMap{"pathEscape": url.PathEscape}
+
+var indexTemplate = template.Must(template.New("index").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head><title>/debug/enums</title></head>
+<body>
+<h1>Enum types</h1>
+<form action="{{.Base}}" method="get">
+<input type="search" name="q" value="{{.Query}}" placeholder="type or member name">
+<input type="submit" value="Search">
+</form>
+<table>
+<tr><th>Type</th><th>Package</th><th>Size</th><th>Members</th></tr>
+{{- range .Types}}
+<tr><td><a href="{{$.Base}}/types/{{pathEscape .Name}}">{{.Name}}</a></td><td>{{.PkgPath}}</td><td>{{.Size}}</td><td>{{range $i, $m := .Members}}{{if $i}}, {{end}}{{$m}}{{end}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+var typeTemplate = template.Must(template.New("type").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head><title>/debug/enums {{.Type.Name}}</title></head>
+<body>
+<p><a href="{{.Base}}">All types</a></p>
+<h1>{{.Type.Name}}</h1>
+<p>Package {{.Type.PkgPath}}, {{.Type.Size}} members{{if .Type.Pointer}}, pointer type{{end}}</p>
+<table>
+<tr><th>Ordinal</th><th>Name</th><th>EnumSet bit</th><th>Fields</th><th>Attributes</th></tr>
+{{- range .Type.Members}}
+<tr><td>{{.Ordinal}}</td><td>{{.Name}}{{if .Deprecated}} (deprecated){{end}}</td><td>word {{.Word}} bit {{.Bit}}</td>
+<td>{{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Name}}={{$f.Value}}{{end}}</td>
+<td>{{range $i, $a := .Attributes}}{{if $i}}, {{end}}{{$a.Name}}={{$a.Value}}{{end}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,96 @@
+package httpdebug
+
+import (
+	"encoding/json"
+	"github.com/lvyahui8/goenum"
+	"github.com/lvyahui8/goenum/internal"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serve(h http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux)
+	t.Run("IndexJSON", func(t *testing.T) {
+		w := serve(mux, "/debug/enums?format=json")
+		require.Equal(t, http.StatusOK, w.Code)
+		var types []TypeSummary
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &types))
+		names := make(map[string]TypeSummary)
+		for _, s := range types {
+			names[s.Name] = s
+		}
+		require.Equal(t, goenum.EnumNames[internal.TradeState](), names["internal.TradeState"].Members)
+		require.True(t, names["*internal.ColorEnum"].Pointer)
+	})
+	t.Run("Search", func(t *testing.T) {
+		w := serve(mux, "/debug/enums/?q=refund", "Accept", "application/json")
+		var types []TypeSummary
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &types))
+		require.Len(t, types, 1)
+		require.Equal(t, "internal.ReverseState", types[0].Name)
+		require.Equal(t, []string{"Refunded"}, types[0].Members)
+	})
+	t.Run("IndexHTML", func(t *testing.T) {
+		w := serve(mux, "/debug/enums/?q=color")
+		require.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		require.Contains(t, w.Body.String(), `<a href="/debug/enums/types/%2Ainternal.ColorEnum">*internal.ColorEnum</a>`)
+		require.NotContains(t, w.Body.String(), "internal.TradeState")
+	})
+	t.Run("TypeJSON", func(t *testing.T) {
+		w := serve(mux, "/debug/enums/types/internal.Code?format=json")
+		require.Equal(t, http.StatusOK, w.Code)
+		var detail Type
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &detail))
+		require.Equal(t, goenum.Size[internal.ErrorCode](), detail.Size)
+		m := detail.Members[2]
+		require.Equal(t, "NetworkError", m.Name)
+		require.Equal(t, 2, m.Ordinal)
+		require.Equal(t, 0, m.Word)
+		require.Equal(t, 2, m.Bit)
+		require.Equal(t, "desc", m.Attributes[1].Name)
+	})
+	t.Run("TypeHTML", func(t *testing.T) {
+		w := serve(mux, "/debug/enums/types/internal.Module")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), "<td>1</td><td>MergeRequests</td><td>word 0 bit 1</td>")
+		require.Contains(t, w.Body.String(), "basePath=/merge/")
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, serve(mux, "/debug/enums/types/internal.Unknown").Code)
+		require.Equal(t, http.StatusNotFound, serve(mux, "/debug/enums/other").Code)
+	})
+}
+
+// Plan 用于测试独立注册表和导出字段
+type Plan struct {
+	goenum.Enum
+	Quota  int
+	Labels []string
+	secret string
+}
+
+func TestRegistry(t *testing.T) {
+	reg := goenum.NewRegistry(nil)
+	goenum.NewEnumIn[Plan](reg, "Free", Plan{Quota: 1, Labels: []string{"basic"}, secret: "x"})
+	h := Handler(reg)
+	w := serve(h, "/tenants/a/debug/enums/types/httpdebug.Plan?format=json")
+	var detail Type
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	require.Equal(t, []Field{{Name: "Quota", Value: "1"}, {Name: "Labels", Value: "[basic]"}}, detail.Members[0].Fields)
+	w = serve(h, "/tenants/a/debug/enums")
+	require.Contains(t, w.Body.String(), `<a href="/tenants/a/debug/enums/types/httpdebug.Plan">httpdebug.Plan</a>`)
+	require.NotContains(t, w.Body.String(), "internal.TradeState")
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type TradeConfig struct {
+	State   TradeState                        `env:"TRADE_STATE" default:"Created"`
+	Final   []TradeState                      `env:"TRADE_FINAL_STATES"`
+	Perms   *goenum.UnsafeEnumSet[Permission] `env:"TRADE_PERMS" default:"ViewMergeRequest"`
+	Color   *ColorEnum                        `env:"TRADE_COLOR"`
+	Timeout time.Duration                     `env:"TRADE_TIMEOUT" default:"3s"`
+	Retry   int                               `env:"TRADE_RETRY"`
+	Server  ServerConfig
+	Ignored string `env:"-"`
+}
+
+type ServerConfig struct {
+	Name  string   `env:"SERVER_NAME" default:"trade"`
+	Debug bool     `env:"SERVER_DEBUG"`
+	Tags  []string `env:"SERVER_TAGS"`
+}
+
+func TestBind(t *testing.T) {
+	t.Run("BindMap", func(t *testing.T) {
+		var conf TradeConfig
+		err := goenum.BindMap(&conf, map[string]string{
+			"TRADE_STATE":        "paid",
+			"TRADE_FINAL_STATES": "Failed, Delivered",
+			"TRADE_PERMS":        "AddLabels,AddTopic",
+			"TRADE_COLOR":        "Red",
+			"TRADE_RETRY":        "3",
+			"SERVER_DEBUG":       "true",
+			"SERVER_TAGS":        "a,b",
+		})
+		require.Nil(t, err)
+		require.True(t, conf.State.Equals(TradePaid))
+		require.Equal(t, []TradeState{TradeFailed, TradeDelivered}, conf.Final)
+		require.Equal(t, "[AddLabels,AddTopic]", conf.Perms.String())
+		require.True(t, conf.Color.Equals(Red))
+		require.Equal(t, 3*time.Second, conf.Timeout)
+		require.Equal(t, 3, conf.Retry)
+		require.Equal(t, "trade", conf.Server.Name)
+		require.True(t, conf.Server.Debug)
+		require.Equal(t, []string{"a", "b"}, conf.Server.Tags)
+	})
+	t.Run("Default", func(t *testing.T) {
+		var conf TradeConfig
+		require.Nil(t, goenum.BindMap(&conf, nil))
+		require.True(t, conf.State.Equals(TradeCreated))
+		require.Equal(t, "[ViewMergeRequest]", conf.Perms.String())
+		require.Nil(t, conf.Color)
+		require.Nil(t, conf.Final)
+	})
+	t.Run("BindEnv", func(t *testing.T) {
+		t.Setenv("TRADE_STATE", "Shipped")
+		t.Setenv("SERVER_NAME", "env")
+		var conf TradeConfig
+		require.Nil(t, goenum.BindEnv(&conf))
+		require.True(t, conf.State.Equals(TradeShipped))
+		require.Equal(t, "env", conf.Server.Name)
+	})
+	t.Run("AggregatedError", func(t *testing.T) {
+		var conf TradeConfig
+		err := goenum.BindMap(&conf, map[string]string{
+			"TRADE_STATE":        "Unknown",
+			"TRADE_FINAL_STATES": "Failed,Foo",
+			"TRADE_PERMS":        "AddLabels,Foo",
+			"TRADE_COLOR":        "Blue",
+			"TRADE_RETRY":        "x",
+		})
+		var bindErr *goenum.BindError
+		require.True(t, errors.As(err, &bindErr))
+		require.Len(t, bindErr.Errors, 5)
+		require.Equal(t, "TradeConfig.State", bindErr.Errors[0].Field)
+		require.Equal(t, "TRADE_STATE", bindErr.Errors[0].Key)
+		require.Equal(t, []string{"Created", "Failed", "Paid", "Shipped", "Delivered"}, bindErr.Errors[0].Allowed)
+		require.Equal(t, "Foo", bindErr.Errors[1].Value)
+		require.Equal(t, "Foo", bindErr.Errors[2].Value)
+		require.Equal(t, []string{"Red", "Yellow"}, bindErr.Errors[3].Allowed)
+		require.NotNil(t, bindErr.Errors[4].Err)
+		require.Contains(t, err.Error(), `TradeConfig.State: invalid value "Unknown" of TRADE_STATE, allowed: Created, Failed, Paid, Shipped, Delivered`)
+		// 无效的切片与EnumSet字段都保持原值
+		require.Nil(t, conf.Final)
+		require.Nil(t, conf.Perms)
+	})
+	t.Run("ReplaceEnumSet", func(t *testing.T) {
+		conf := TradeConfig{Perms: goenum.NewUnsafeEnumSet[Permission]()}
+		conf.Perms.Add(AddTopic)
+		perms := conf.Perms
+		require.NotNil(t, goenum.BindMap(&conf, map[string]string{"TRADE_PERMS": "AddLabels,Foo"}))
+		require.Same(t, perms, conf.Perms)
+		require.Equal(t, "[AddTopic]", conf.Perms.String())
+		require.Nil(t, goenum.BindMap(&conf, map[string]string{"TRADE_PERMS": "AddLabels"}))
+		require.Equal(t, "[AddLabels]", conf.Perms.String())
+	})
+	t.Run("InvalidDefault", func(t *testing.T) {
+		var conf struct {
+			State TradeState `env:"STATE" default:"Unknown"`
+		}
+		require.NotNil(t, goenum.BindMap(&conf, nil))
+	})
+	t.Run("InvalidTarget", func(t *testing.T) {
+		var conf TradeConfig
+		require.NotNil(t, goenum.BindMap(conf, nil))
+		require.NotNil(t, goenum.BindMap((*TradeConfig)(nil), nil))
+	})
+}
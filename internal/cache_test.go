@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValueOfOrdinal(t *testing.T) {
+	for i, e := range goenum.Values[TradeState]() {
+		v, valid := goenum.ValueOfOrdinal[TradeState](i)
+		require.True(t, valid)
+		require.Equal(t, e, v)
+	}
+	_, valid := goenum.ValueOfOrdinal[TradeState](-1)
+	require.False(t, valid)
+	_, valid = goenum.ValueOfOrdinal[TradeState](goenum.Size[TradeState]())
+	require.False(t, valid)
+	red, valid := goenum.ValueOfOrdinal[*ColorEnum](0)
+	require.True(t, valid)
+	require.Equal(t, Red, red)
+}
+
+func TestValuesView(t *testing.T) {
+	view := goenum.ValuesView[TradeState]()
+	require.Equal(t, goenum.Values[TradeState](), view)
+	// 追加元素不会修改共享的切片
+	_ = append(view, TradeState{})
+	require.Equal(t, goenum.Size[TradeState](), len(goenum.ValuesView[TradeState]()))
+	require.Nil(t, goenum.ValuesView[Plan]())
+}
+
+// TestValueOfIgnoreCase_Fold 与strings.EqualFold一致地折叠非ASCII字符，不依赖逐个比较
+func TestValueOfIgnoreCase_Fold(t *testing.T) {
+	reg := goenum.NewRegistry(nil)
+	kilo := goenum.NewEnumIn[Plan](reg, "Kilo")
+	straße := goenum.NewEnumIn[Plan](reg, "Straße")
+	sigma := goenum.NewEnumIn[Plan](reg, "Σίγμα")
+	for name, want := range map[string]Plan{
+		"KILO": kilo, "\u212Ailo": kilo, "kilo": kilo,
+		"STRAßE": straße, "ſtraße": straße,
+		"σίγμα": sigma, "ΣΊΓΜΑ": sigma, "ςίγμα": sigma,
+	} {
+		v, valid := goenum.ValueOfIgnoreCaseIn[Plan](reg, name)
+		require.True(t, valid, name)
+		require.Equal(t, want, v, name)
+	}
+	// ß与SS不是简单折叠关系，与strings.EqualFold一致
+	_, valid := goenum.ValueOfIgnoreCaseIn[Plan](reg, "STRASSE")
+	require.False(t, valid)
+}
+
+// TestCacheInvalidation 注册新成员后缓存重建
+func TestCacheInvalidation(t *testing.T) {
+	parent := goenum.NewRegistry(nil)
+	child := goenum.NewRegistry(parent)
+	goenum.NewEnumIn[Plan](parent, "Free")
+	require.Equal(t, 1, goenum.SizeIn[Plan](child))
+	_, valid := goenum.ValueOfIgnoreCaseIn[Plan](child, "PRO")
+	require.False(t, valid)
+
+	pro := goenum.NewEnumIn[Plan](child, "Pro")
+	v, valid := goenum.ValueOfIgnoreCaseIn[Plan](child, "PRO")
+	require.True(t, valid)
+	require.Equal(t, pro, v)
+	v, valid = goenum.ValueOfOrdinalIn[Plan](child, 1)
+	require.True(t, valid)
+	require.Equal(t, pro, v)
+
+	// 子注册表扩展了Plan后，父注册表不能再注册Plan，否则新成员与子注册表的成员序号冲突
+	require.PanicsWithValue(t, "goenum: internal.Plan is extended by a child registry, its members must be registered before the children's", func() {
+		goenum.NewEnumIn[Plan](parent, "Team")
+	})
+	values := goenum.ValuesViewIn[Plan](child)
+	require.Equal(t, []string{"Free", "Pro"}, goenum.EnumNames(values...))
+	for i, v := range values {
+		require.Equal(t, i, v.Ordinal())
+	}
+
+	// 父注册表的变化同样使子注册表的缓存失效
+	parent = goenum.NewRegistry(nil)
+	child = goenum.NewRegistry(parent)
+	goenum.NewEnumIn[Plan](parent, "Free")
+	require.Equal(t, 1, goenum.SizeIn[Plan](child))
+	goenum.NewEnumIn[Plan](parent, "Team")
+	require.Equal(t, []string{"Free", "Team"}, goenum.EnumNames(goenum.ValuesViewIn[Plan](child)...))
+	require.True(t, goenum.IsValidEnumIn[Plan](child, "Team"))
+	require.Equal(t, 2, goenum.NewEnumIn[Plan](child, "Pro").Ordinal())
+}
+
+func TestValueOfInterface(t *testing.T) {
+	e, valid := goenum.ValueOf[goenum.EnumDefinition]("Owner")
+	require.True(t, valid)
+	require.Equal(t, Owner, e)
+	_, valid = goenum.ValueOf[goenum.EnumDefinition]("Unknown")
+	require.False(t, valid)
+}
+
+// BenchmarkLookup
+//
+//	go test ./internal -run=^$ -bench=BenchmarkLookup -benchmem
+func BenchmarkLookup(b *testing.B) {
+	b.Run("ValueOf", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = goenum.ValueOf[Permission]("DeleteMergeRequest")
+		}
+	})
+	b.Run("ValueOfIgnoreCase", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = goenum.ValueOfIgnoreCase[Permission]("deletemergerequest")
+		}
+	})
+	b.Run("ValueOfOrdinal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = goenum.ValueOfOrdinal[Permission](4)
+		}
+	})
+	b.Run("Values", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = goenum.Values[Permission]()
+		}
+	})
+	b.Run("ValuesView", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = goenum.ValuesView[Permission]()
+		}
+	})
+	b.Run("Size", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = goenum.Size[Permission]()
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"sort"
+	"testing"
+)
+
+// sortFunc 与slices.SortFunc相同，兼容go1.20
+func sortFunc[T any](list []T, cmp func(a, b T) int) []T {
+	sort.SliceStable(list, func(i, j int) bool {
+		return cmp(list[i], list[j]) < 0
+	})
+	return list
+}
+
+func TestComparators(t *testing.T) {
+	states := []TradeState{TradeShipped, TradeCreated, TradeDelivered, TradePaid, TradeFailed}
+	t.Run("Ordinal", func(t *testing.T) {
+		require.Equal(t, goenum.Values[TradeState](), sortFunc(states, goenum.CompareOrdinal[TradeState]))
+	})
+	t.Run("Name", func(t *testing.T) {
+		require.Equal(t, []TradeState{TradeCreated, TradeDelivered, TradeFailed, TradePaid, TradeShipped},
+			sortFunc(states, goenum.CompareName[TradeState]))
+	})
+	t.Run("Attribute", func(t *testing.T) {
+		codes := sortFunc(goenum.Values[ErrorCode](), goenum.ByAttribute[ErrorCode]("code"))
+		require.Equal(t, []ErrorCode{Failed, Success, Payment, Trade, Delivery, NetworkError, EncodeError}, codes)
+		// 属性相同时按序号排序，false排在true之前
+		require.Equal(t, []TradeState{TradeCreated, TradePaid, TradeShipped, TradeFailed, TradeDelivered},
+			sortFunc(states, goenum.ByAttribute[TradeState]("isFinal")))
+		require.PanicsWithValue(t, "goenum: internal.TradeState has no attribute priority", func() {
+			goenum.ByAttribute[TradeState]("priority")
+		})
+	})
+	t.Run("Order", func(t *testing.T) {
+		cmp := goenum.ByOrder(TradePaid, TradeCreated, TradePaid)
+		require.Equal(t, []TradeState{TradePaid, TradeCreated, TradeFailed, TradeShipped, TradeDelivered}, sortFunc(states, cmp))
+	})
+}
+
+func TestCompare_TypeMismatch(t *testing.T) {
+	_, err := goenum.CompareChecked(TradePaid, ReverseFailed)
+	var mismatch *goenum.TypeMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, "goenum: can not compare internal.TradeState with internal.ReverseState", err.Error())
+	c, err := goenum.CompareChecked(TradePaid, TradeCreated)
+	require.Nil(t, err)
+	require.True(t, c > 0)
+	// 零值没有类型，可以与任意枚举比较
+	_, err = goenum.CompareChecked(TradeState{}, ReverseFailed)
+	require.Nil(t, err)
+
+	// Compare同样拒绝不同类型的枚举，而不是返回序号差
+	require.PanicsWithError(t, "goenum: can not compare internal.TradeState with internal.ReverseState", func() {
+		TradePaid.Compare(ReverseFailed)
+	})
+	require.True(t, TradePaid.Compare(TradeCreated) > 0)
+	require.Equal(t, TradePaid.Ordinal(), TradePaid.Compare(TradeState{}))
+	require.Panics(t, func() {
+		goenum.CompareOrdinal[goenum.EnumDefinition](TradePaid, ReverseFailed)
+	})
+	require.Panics(t, func() {
+		goenum.ByOrder[goenum.EnumDefinition](TradePaid)(TradePaid, ReverseFailed)
+	})
+}
+
+func TestMinMaxClamp(t *testing.T) {
+	require.Equal(t, TradeFailed, goenum.Min(TradeShipped, TradeFailed, TradePaid))
+	require.Equal(t, TradeShipped, goenum.Max(TradeShipped, TradeFailed, TradePaid))
+	require.Equal(t, TradePaid, goenum.Min(TradePaid))
+	require.Equal(t, TradePaid, goenum.Clamp(TradeCreated, TradePaid, TradeShipped))
+	require.Equal(t, TradeShipped, goenum.Clamp(TradeDelivered, TradePaid, TradeShipped))
+	require.Equal(t, TradeShipped, goenum.Clamp(TradeShipped, TradePaid, TradeShipped))
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		d, ok := goenum.Describe[ErrorCode]()
+		require.True(t, ok)
+		require.Equal(t, "internal.Code", d.Name)
+		require.Equal(t, "github.com/lvyahui8/goenum/internal", d.PkgPath)
+		require.Equal(t, reflect.TypeOf(ErrorCode{}), d.Type)
+		require.False(t, d.Pointer)
+		require.Equal(t, goenum.Size[ErrorCode](), d.Size)
+		require.Equal(t, NetworkError, d.Members[2].Value)
+		data, err := json.MarshalIndent(d, "", "  ")
+		require.Nil(t, err)
+		requireGolden(t, "describe/code.json", append(data, '\n'))
+	})
+	t.Run("Pointer", func(t *testing.T) {
+		d, ok := goenum.Describe[*ColorEnum]()
+		require.True(t, ok)
+		require.Equal(t, "*internal.ColorEnum", d.Name)
+		require.Equal(t, "github.com/lvyahui8/goenum/internal", d.PkgPath)
+		require.True(t, d.Pointer)
+		require.Equal(t, "Yellow", d.Members[1].Name)
+	})
+	t.Run("Unregistered", func(t *testing.T) {
+		_, ok := goenum.Describe[Plan]()
+		require.False(t, ok)
+	})
+	t.Run("Types", func(t *testing.T) {
+		var names []string
+		for _, d := range goenum.Types() {
+			names = append(names, d.Name)
+			require.Equal(t, d.Size, len(d.Members))
+		}
+		require.Subset(t, names, []string{"*internal.ColorEnum", "internal.Code", "internal.TradeState", "internal.Role"})
+		require.IsIncreasing(t, names)
+	})
+	t.Run("Registry", func(t *testing.T) {
+		reg := goenum.NewRegistry(nil)
+		goenum.NewEnumIn[Plan](reg, "Free")
+		types := reg.Types()
+		require.Len(t, types, 1)
+		require.Equal(t, "internal.Plan", types[0].Name)
+		child := goenum.NewRegistry(reg)
+		goenum.NewEnumIn[Plan](child, "Pro")
+		d, ok := goenum.DescribeIn[Plan](child)
+		require.True(t, ok)
+		require.Equal(t, []string{"Free", "Pro"}, []string{d.Members[0].Name, d.Members[1].Name})
+	})
+}
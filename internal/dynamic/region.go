@@ -0,0 +1,30 @@
+package dynamic
+
+import "github.com/lvyahui8/goenum"
+
+// Region 部分成员在代码中静态声明，其余成员从配置文件动态加载
+type Region struct {
+	goenum.Enum
+	Zone     string
+	Capacity int
+	Weight   float64
+	Enabled  bool
+	Aliases  []string
+}
+
+var (
+	Beijing  = goenum.NewEnum[Region]("Beijing", Region{Zone: "north", Capacity: 100, Enabled: true})
+	Shanghai = goenum.NewEnum[Region]("Shanghai", Region{Zone: "east", Capacity: 200, Enabled: true})
+)
+
+// RegionSchema 动态Region成员允许的属性
+var RegionSchema = goenum.AttrSchema{
+	"zone":     {Kind: goenum.AttrString, Required: true},
+	"capacity": {Kind: goenum.AttrInt, Required: true},
+	"weight":   {Kind: goenum.AttrFloat},
+	"enabled":  {Kind: goenum.AttrBool},
+	"aliases":  {Kind: goenum.AttrStrings},
+}
+
+// Regions 使用属性同名导出字段构造成员
+var Regions = goenum.NewDynamicType[Region](RegionSchema, nil)
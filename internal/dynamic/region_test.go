@@ -0,0 +1,115 @@
+package dynamic
+
+import (
+	"embed"
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/regions.yaml
+var definitions embed.FS
+
+// TestDynamicType 各用例按顺序执行，后加载的成员排在已注册成员之后
+func TestDynamicType(t *testing.T) {
+	t.Run("LoadFile", func(t *testing.T) {
+		regions, err := Regions.LoadFile("testdata/regions.json")
+		require.Nil(t, err)
+		require.Len(t, regions, 2)
+		shenzhen, valid := goenum.ValueOf[Region]("Shenzhen")
+		require.True(t, valid)
+		require.Equal(t, 2, shenzhen.Ordinal())
+		require.Equal(t, "south", shenzhen.Zone)
+		require.Equal(t, 300, shenzhen.Capacity)
+		require.Equal(t, 0.5, shenzhen.Weight)
+		require.True(t, shenzhen.Enabled)
+		require.True(t, regions[0].Equals(shenzhen))
+		require.Equal(t, []string{"Rongcheng"}, regions[1].Aliases)
+		require.Equal(t, 3, regions[1].Ordinal())
+		require.Equal(t, []string{"Beijing", "Shanghai", "Shenzhen", "Chengdu"}, goenum.EnumNames[Region]())
+	})
+	t.Run("LoadFS", func(t *testing.T) {
+		regions, err := Regions.LoadFS(definitions, "testdata/regions.yaml")
+		require.Nil(t, err)
+		require.Equal(t, 4, regions[0].Ordinal())
+		require.Equal(t, float64(1), regions[0].Weight)
+		require.Equal(t, 5, goenum.Size[Region]())
+	})
+	t.Run("Builder", func(t *testing.T) {
+		regions := goenum.NewDynamicType[Region](RegionSchema, func(name string, attrs goenum.Attrs) (Region, error) {
+			if attrs.Int("capacity") <= 0 {
+				return Region{}, errors.New("capacity must be positive")
+			}
+			return Region{Zone: strings.ToUpper(attrs.String("zone")), Capacity: int(attrs.Int("capacity"))}, nil
+		})
+		res, err := regions.LoadJSON(strings.NewReader(`{"members":[{"name":"Wuhan","attributes":{"zone":"central","capacity":10}}]}`))
+		require.Nil(t, err)
+		require.Equal(t, "CENTRAL", res[0].Zone)
+		_, err = regions.LoadYAML(strings.NewReader("members:\n  - name: Xian\n    attributes: {zone: west, capacity: 0}\n"))
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "members[0]: capacity must be positive")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		size := goenum.Size[Region]()
+		_, err := Regions.LoadFile("testdata/invalid.yaml")
+		var defErr *goenum.DefinitionError
+		require.True(t, errors.As(err, &defErr))
+		require.Equal(t, []string{
+			`type "internal.TradeState" mismatches dynamic.Region`,
+			`members[0].name: duplicate enum "Beijing"`,
+			`members[1].name: required`,
+			"members[1].ordinal: 1 collides with the ordinals of dynamic.Region, the next ordinal is 7",
+			"members[1].attributes.capacity: expect int, got many",
+			"members[1].attributes.unknown: undeclared attribute",
+			"members[1].attributes.zone: expect string, got 1",
+			"members[2].attributes.capacity: required",
+			"members[2].attributes.zone: required",
+		}, defErr.Problems)
+		// 定义非法时不注册任何成员
+		require.Equal(t, size, goenum.Size[Region]())
+		require.False(t, goenum.IsValidEnum[Region]("Guangzhou"))
+	})
+	t.Run("Unreadable", func(t *testing.T) {
+		_, err := Regions.LoadFile("testdata/not_exist.json")
+		require.NotNil(t, err)
+		_, err = Regions.LoadJSON(strings.NewReader("{"))
+		require.NotNil(t, err)
+	})
+	t.Run("UnassignableAttribute", func(t *testing.T) {
+		type Host struct {
+			goenum.Enum
+			zone string
+		}
+		hosts := goenum.NewDynamicType[Host](goenum.AttrSchema{"zone": {Kind: goenum.AttrString}}, nil)
+		_, err := hosts.LoadJSON(strings.NewReader(`{"members":[{"name":"a","attributes":{"zone":"x"}}]}`))
+		require.NotNil(t, err)
+		require.True(t, reflect.DeepEqual([]Host(nil), goenum.Values[Host]()))
+	})
+	t.Run("LossyAttribute", func(t *testing.T) {
+		type Host struct {
+			goenum.Enum
+			Label string
+			Port  uint8
+			Ratio int
+		}
+		hosts := goenum.NewDynamicType[Host](goenum.AttrSchema{
+			"label": {Kind: goenum.AttrInt},
+			"port":  {Kind: goenum.AttrInt, Required: true},
+			"ratio": {Kind: goenum.AttrFloat},
+		}, nil)
+		// 65不能变成"A"，2.9不能截断为2，超出范围的端口也不能回绕
+		_, err := hosts.LoadFile("testdata/lossy.yaml")
+		var defErr *goenum.DefinitionError
+		require.True(t, errors.As(err, &defErr))
+		require.Equal(t, []string{
+			`members[0]: attribute "label" can not be assigned to field Label of string`,
+			`members[1]: attribute "port" value 300 overflows field Port of uint8`,
+			`members[2]: attribute "port" value -1 overflows field Port of uint8`,
+			`members[3]: attribute "ratio" can not be assigned to field Ratio of int`,
+		}, defErr.Problems)
+		require.True(t, reflect.DeepEqual([]Host(nil), goenum.Values[Host]()))
+	})
+}
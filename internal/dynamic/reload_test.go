@@ -0,0 +1,256 @@
+package dynamic
+
+import (
+	"context"
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Cluster 全部成员来自可热加载的配置文件
+type Cluster struct {
+	goenum.Enum
+	Zone     string
+	Capacity int
+}
+
+var Local = goenum.NewEnum[Cluster]("Local", Cluster{Zone: "local", Capacity: 1})
+
+var clusterSchema = goenum.AttrSchema{
+	"zone":     {Kind: goenum.AttrString, Required: true},
+	"capacity": {Kind: goenum.AttrInt},
+}
+
+func writeDefinition(t *testing.T, path string, content string) {
+	require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func names(enums []goenum.EnumDefinition) (res []string) {
+	for _, e := range enums {
+		res = append(res, e.Name())
+	}
+	return
+}
+
+func cluster(name string) Cluster {
+	c, _ := goenum.ValueOf[Cluster](name)
+	return c
+}
+
+func TestReload(t *testing.T) {
+	clusters := goenum.NewDynamicType[Cluster](clusterSchema, nil)
+	events, cancel := goenum.Watch[Cluster]()
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "clusters.yaml")
+
+	writeDefinition(t, path, `members:
+  - {name: Alpha, attributes: {zone: north, capacity: 10}}
+  - {name: Beta, attributes: {zone: south, capacity: 20}}
+`)
+	event, err := clusters.ReloadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, "dynamic.Cluster", event.Type)
+	require.Equal(t, []string{"Alpha", "Beta"}, names(event.Added))
+	require.Equal(t, event, <-events)
+	alpha, _ := goenum.ValueOf[Cluster]("Alpha")
+	beta, _ := goenum.ValueOf[Cluster]("Beta")
+	require.Equal(t, 1, alpha.Ordinal())
+	require.Equal(t, 2, beta.Ordinal())
+
+	// 修改Alpha，删除Beta，新增Gamma
+	writeDefinition(t, path, `members:
+  - {name: Alpha, attributes: {zone: east, capacity: 10}}
+  - {name: Gamma, ordinal: 3, attributes: {zone: west}}
+`)
+	event, err = clusters.ReloadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, event, <-events)
+	require.Equal(t, []string{"Gamma"}, names(event.Added))
+	require.Equal(t, []string{"Beta"}, names(event.Removed))
+	require.Len(t, event.Changed, 1)
+	require.Equal(t, []string{"zone"}, event.Changed[0].Attributes)
+	require.Equal(t, "north", event.Changed[0].Old.(Cluster).Zone)
+	require.Equal(t, "east", event.Changed[0].New.(Cluster).Zone)
+
+	newAlpha, _ := goenum.ValueOf[Cluster]("Alpha")
+	require.Equal(t, "east", newAlpha.Zone)
+	require.True(t, newAlpha.Equals(alpha))
+	require.Equal(t, alpha.Ordinal(), newAlpha.Ordinal())
+	// 被删除的成员保留为墓碑，已有引用和序号不受影响
+	require.True(t, goenum.IsDeprecated(beta))
+	require.False(t, goenum.IsDeprecated(newAlpha))
+	tombstone, valid := goenum.ValueOf[Cluster]("Beta")
+	require.True(t, valid)
+	require.True(t, tombstone.Equals(beta))
+	require.Equal(t, []string{"Local", "Alpha", "Beta", "Gamma"}, goenum.EnumNames[Cluster]())
+
+	// 重新定义被删除的成员
+	writeDefinition(t, path, `members:
+  - {name: Alpha, attributes: {zone: east, capacity: 10}}
+  - {name: Beta, attributes: {zone: south, capacity: 30}}
+  - {name: Gamma, attributes: {zone: west}}
+`)
+	event, err = clusters.ReloadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, event, <-events)
+	require.Equal(t, []string{"Beta"}, names(event.Added))
+	require.Empty(t, event.Removed)
+	require.Empty(t, event.Changed)
+	require.False(t, goenum.IsDeprecated(beta))
+	beta, _ = goenum.ValueOf[Cluster]("Beta")
+	require.Equal(t, 2, beta.Ordinal())
+	require.Equal(t, 30, beta.Capacity)
+
+	// 内容不变时不发送事件
+	event, err = clusters.ReloadFile(path)
+	require.Nil(t, err)
+	require.True(t, event.IsEmpty())
+	require.Len(t, events, 0)
+}
+
+func TestReloadInvalid(t *testing.T) {
+	clusters := goenum.NewDynamicType[Cluster](clusterSchema, nil)
+	_, err := clusters.ReloadJSON(strings.NewReader(`{"members":[{"name":"Delta","attributes":{"zone":"x"}}]}`))
+	require.Nil(t, err)
+	before := goenum.Values[Cluster]()
+	_, err = clusters.ReloadJSON(strings.NewReader(`{"members":[
+		{"name":"Delta","ordinal":0,"attributes":{"zone":"y"}},
+		{"name":"Local","attributes":{"zone":"z"}},
+		{"name":"Epsilon","attributes":{}}]}`))
+	var defErr *goenum.DefinitionError
+	require.True(t, errors.As(err, &defErr))
+	require.Equal(t, []string{
+		"members[0].ordinal: 0 mismatches the ordinal " + strconv.Itoa(cluster("Delta").Ordinal()) + " of Delta",
+		`members[1].name: duplicate enum "Local"`,
+		"members[2].attributes.zone: required",
+	}, defErr.Problems)
+	// 定义非法时注册表保持不变
+	require.Equal(t, before, goenum.Values[Cluster]())
+	require.False(t, goenum.IsDeprecated(cluster("Delta")))
+}
+
+func TestWatchFile(t *testing.T) {
+	clusters := goenum.NewDynamicType[Cluster](clusterSchema, nil)
+	events, cancel := goenum.Watch[Cluster]()
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "clusters.json")
+	writeDefinition(t, path, `{"members":[{"name":"Zeta","attributes":{"zone":"a"}}]}`)
+
+	ctx, stop := context.WithCancel(context.Background())
+	var errs []error
+	var mu sync.Mutex
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		clusters.WatchFile(ctx, path, 5*time.Millisecond, func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		})
+	}()
+	event := receive(t, events)
+	require.Equal(t, []string{"Zeta"}, names(event.Added))
+
+	writeDefinition(t, path, `{"members":[{"name":"Zeta","attributes":{"zone":"b", "capacity": 100}}]}`)
+	event = receive(t, events)
+	require.Equal(t, []string{"capacity", "zone"}, event.Changed[0].Attributes)
+	require.Equal(t, "b", cluster("Zeta").Zone)
+	stop()
+	<-done
+	mu.Lock()
+	require.Empty(t, errs)
+	mu.Unlock()
+}
+
+func receive(t *testing.T, events <-chan goenum.ChangeEvent) goenum.ChangeEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change event received")
+		return goenum.ChangeEvent{}
+	}
+}
+
+// TestSnapshot 并发读取时总能看到一致的快照
+func TestSnapshot(t *testing.T) {
+	clusters := goenum.NewDynamicType[Cluster](clusterSchema, nil)
+	ctx, stop := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			values := goenum.Values[Cluster]()
+			for i, v := range values {
+				if v.Ordinal() != i {
+					t.Errorf("inconsistent snapshot: %s at %d", v.Name(), i)
+					return
+				}
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		_, err := clusters.ReloadJSON(strings.NewReader(`{"members":[{"name":"Snapshot` + strconv.Itoa(i) + `","attributes":{"zone":"s"}}]}`))
+		require.Nil(t, err)
+	}
+	stop()
+	wg.Wait()
+}
+
+// TestWatchSlowSubscriber 不消费事件的订阅者只阻塞同一类型的重新加载
+func TestWatchSlowSubscriber(t *testing.T) {
+	tenant := goenum.NewRegistry(nil)
+	events, cancel := goenum.WatchIn[Cluster](tenant)
+	defer cancel()
+	clusters := goenum.NewDynamicTypeIn[Cluster](tenant, clusterSchema, nil)
+	load := func(i int) {
+		_, err := clusters.LoadJSON(strings.NewReader(`{"members":[{"name":"Slow` + strconv.Itoa(i) + `","attributes":{"zone":"s"}}]}`))
+		require.Nil(t, err)
+	}
+	// 填满订阅者的缓冲区，之后的事件阻塞在发送上
+	n := cap(events) + 2
+	for i := 0; i < cap(events); i++ {
+		load(i)
+	}
+	blocked := make(chan struct{})
+	go func() {
+		defer close(blocked)
+		for i := cap(events); i < n; i++ {
+			load(i)
+		}
+	}()
+	require.Eventually(t, func() bool {
+		return goenum.IsValidEnumIn[Cluster](tenant, "Slow"+strconv.Itoa(cap(events)))
+	}, 5*time.Second, time.Millisecond)
+
+	regions := make(chan error)
+	go func() {
+		_, err := goenum.NewDynamicTypeIn[Region](tenant, RegionSchema, nil).
+			LoadJSON(strings.NewReader(`{"members":[{"name":"Slow","attributes":{"zone":"s","capacity":1}}]}`))
+		regions <- err
+	}()
+	select {
+	case err := <-regions:
+		require.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the reload of Region is blocked by the subscriber of Cluster")
+	}
+	select {
+	case <-blocked:
+		t.Fatal("the reload of Cluster is not blocked by its subscriber")
+	default:
+	}
+	// 事件按加载的顺序送达
+	for i := 0; i < n; i++ {
+		require.Equal(t, []string{"Slow" + strconv.Itoa(i)}, names((<-events).Added))
+	}
+	<-blocked
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEnumMap(t *testing.T) {
+	m := goenum.NewEnumMap[TradeState, int]()
+	_, existed := m.Put(TradeShipped, 3)
+	require.False(t, existed)
+	m.Put(TradeCreated, 1)
+	old, existed := m.Put(TradeShipped, 4)
+	require.True(t, existed)
+	require.Equal(t, 3, old)
+	require.Equal(t, 2, m.Len())
+	v, ok := m.Get(TradeShipped)
+	require.True(t, ok)
+	require.Equal(t, 4, v)
+	_, ok = m.Get(TradePaid)
+	require.False(t, ok)
+	require.True(t, m.Contains(TradeCreated))
+	require.Equal(t, []TradeState{TradeCreated, TradeShipped}, m.Keys())
+	require.Equal(t, "{Created:1,Shipped:4}", m.String())
+	data, err := json.Marshal(m)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"Created":1,"Shipped":4}`, string(data))
+	require.True(t, m.Remove(TradeCreated))
+	require.False(t, m.Remove(TradeCreated))
+	require.Equal(t, 1, m.Len())
+
+	// 创建后注册的成员
+	reg := goenum.NewRegistry(nil)
+	plans := goenum.NewEnumMapIn[Plan, string](reg)
+	pro := goenum.NewEnumIn[Plan](reg, "Pro")
+	plans.Put(pro, "pro")
+	v2, ok := plans.Get(pro)
+	require.True(t, ok)
+	require.Equal(t, "pro", v2)
+	require.Equal(t, []Plan{pro}, plans.Keys())
+	require.Equal(t, "{Pro:pro}", plans.String())
+}
+
+func TestEnumMap_InvalidKey(t *testing.T) {
+	m := goenum.NewEnumMap[TradeState, int]()
+	m.Put(TradeCreated, 1)
+	// 零值的序号为0，不能当作TradeCreated
+	require.PanicsWithValue(t, `goenum: "" is not a registered member of internal.TradeState`, func() {
+		m.Put(TradeState{}, 2)
+	})
+	_, ok := m.Get(TradeState{})
+	require.False(t, ok)
+	require.False(t, m.Remove(TradeState{}))
+	v, ok := m.Get(TradeCreated)
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+
+	// 其他注册表的成员
+	reg := goenum.NewRegistry(nil)
+	pro := goenum.NewEnumIn[Plan](reg, "Pro")
+	plans := goenum.NewEnumMap[Plan, string]()
+	require.PanicsWithValue(t, `goenum: "Pro" is not a registered member of internal.Plan`, func() {
+		plans.Put(pro, "pro")
+	})
+	_, ok = plans.Get(pro)
+	require.False(t, ok)
+	require.False(t, plans.Remove(pro))
+	require.Equal(t, 0, plans.Len())
+}
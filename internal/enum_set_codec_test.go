@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseEnumSet(t *testing.T) {
+	expected := goenum.NewUnsafeEnumSet[Permission]()
+	expected.Add(AddLabels)
+	expected.Add(ViewMergeRequest)
+	for _, s := range []string{
+		expected.String(),
+		"AddLabels,ViewMergeRequest",
+		" addLabels , viewmergerequest ",
+		"0x5",
+		"5",
+		"0b101",
+	} {
+		set, err := goenum.ParseEnumSet[Permission](s)
+		require.Nil(t, err, s)
+		require.True(t, expected.Equals(set), s)
+	}
+	for _, s := range []string{"", "[]", "0"} {
+		set, err := goenum.ParseEnumSet[Permission](s)
+		require.Nil(t, err, s)
+		require.True(t, set.IsEmpty(), s)
+	}
+
+	_, err := goenum.ParseEnumSet[Permission]("AddLabels,Foo,Bar")
+	var unknown *goenum.UnknownMembersError
+	require.True(t, errors.As(err, &unknown))
+	require.Equal(t, "internal.Permission", unknown.Type)
+	require.Equal(t, []string{"Foo", "Bar"}, unknown.Names)
+	require.Equal(t, `goenum: unknown members of internal.Permission: names "Foo", "Bar"`, err.Error())
+
+	// Permission只有5个成员，第5、7位不存在
+	_, err = goenum.ParseEnumSet[Permission]("0xa1")
+	require.True(t, errors.As(err, &unknown))
+	require.Equal(t, []int{5, 7}, unknown.Ordinals)
+	require.Equal(t, "goenum: unknown members of internal.Permission: ordinals 5, 7", err.Error())
+
+	// 位数超过成员数的位图在解析前被拒绝，错误信息不随输入增长
+	for _, s := range []string{"0x1" + strings.Repeat("0", 400000), "0b111111", "0o77", "255", "1" + strings.Repeat("_0", 10)} {
+		_, err = goenum.ParseEnumSet[Permission](s)
+		require.Error(t, err, s)
+		require.Less(t, len(err.Error()), 100, s)
+	}
+	// 前导零不计入位数
+	set, err := goenum.ParseEnumSet[Permission]("0x" + strings.Repeat("0", 100) + "1f")
+	require.Nil(t, err)
+	require.Equal(t, 5, set.Len())
+	_, err = goenum.ParseEnumSet[Permission]("0x100")
+	require.EqualError(t, err, "goenum: bitmask of 3 digits exceeds the 5 members of internal.Permission")
+	set = goenum.NewUnsafeEnumSet[Permission]()
+	require.EqualError(t, set.UnmarshalJSON([]byte("1"+strings.Repeat("0", 1000))),
+		"goenum: bitmask of 1001 digits exceeds the 5 members of internal.Permission")
+	require.Nil(t, set.UnmarshalJSON([]byte("31")))
+	require.Equal(t, 5, set.Len())
+}
+
+func TestEnumSet_IntegerName(t *testing.T) {
+	reg := goenum.NewRegistry(nil)
+	goenum.NewEnumIn[Permission](reg, "View")
+	goenum.NewEnumIn[Permission](reg, "200")
+	goenum.NewEnumIn[Permission](reg, "2xx")
+	// 整数名称优先按名称解析，没有同名成员时才是位图
+	for s, want := range map[string]string{
+		"200":      "[200]",
+		"[200]":    "[200]",
+		"200,View": "[View,200]",
+		"0b11":     "[View,200]",
+		"1":        "[View]",
+		"2xx":      "[2xx]",
+		"0x7":      "[View,200,2xx]",
+		"[0b1_01]": "[View,2xx]",
+	} {
+		set, err := goenum.ParseEnumSetIn[Permission](reg, s)
+		require.Nil(t, err, s)
+		require.Equal(t, want, set.String(), s)
+	}
+	set := goenum.NewUnsafeEnumSetIn[Permission](reg)
+	require.Nil(t, set.UnmarshalText([]byte("200")))
+	require.Equal(t, "[200]", set.String())
+	// JSON数字总是位图，名称是字符串
+	require.Nil(t, set.UnmarshalJSON([]byte("2")))
+	require.Equal(t, "[200]", set.String())
+	require.Nil(t, set.UnmarshalJSON([]byte(`"200"`)))
+	require.Equal(t, "[200]", set.String())
+	require.Nil(t, set.UnmarshalJSON([]byte("3")))
+	require.Equal(t, "[View,200]", set.String())
+}
+
+type permHolder struct {
+	Perms *goenum.UnsafeEnumSet[Permission] `json:"perms"`
+}
+
+func TestEnumSet_JSON(t *testing.T) {
+	set := goenum.NewUnsafeEnumSet[Permission]()
+	set.Add(AddTopic)
+	set.Add(DeleteMergeRequest)
+	for _, c := range []struct {
+		encoding goenum.SetEncoding
+		json     string
+		text     string
+	}{
+		{goenum.EncodeNames, `["AddTopic","DeleteMergeRequest"]`, "AddTopic,DeleteMergeRequest"},
+		{goenum.EncodeHex, `"0x12"`, "0x12"},
+		{goenum.EncodeInt, `18`, "18"},
+	} {
+		data, err := json.Marshal(permHolder{Perms: set.WithEncoding(c.encoding)})
+		require.Nil(t, err)
+		require.Equal(t, `{"perms":`+c.json+`}`, string(data))
+		var holder permHolder
+		require.Nil(t, json.Unmarshal(data, &holder))
+		require.True(t, set.Equals(holder.Perms))
+
+		text, err := set.MarshalText()
+		require.Nil(t, err)
+		require.Equal(t, c.text, string(text))
+		decoded := goenum.NewUnsafeEnumSet[Permission]()
+		require.Nil(t, decoded.UnmarshalText(text))
+		require.True(t, set.Equals(decoded))
+	}
+	// 编码方式随Clone复制
+	clone, ok := set.Clone().(*goenum.UnsafeEnumSet[Permission])
+	require.True(t, ok)
+	text, _ := clone.MarshalText()
+	require.Equal(t, "18", string(text))
+
+	// 零值集合可以直接解码，失败时保持原有成员
+	var holder struct {
+		Perms goenum.UnsafeEnumSet[Permission]
+	}
+	require.Nil(t, json.Unmarshal([]byte(`{"Perms":"[AddLabels]"}`), &holder))
+	require.True(t, holder.Perms.Contains(AddLabels))
+	err := json.Unmarshal([]byte(`{"Perms":["AddTopic","Unknown"]}`), &holder)
+	var unknown *goenum.UnknownMembersError
+	require.True(t, errors.As(err, &unknown))
+	require.Equal(t, []string{"Unknown"}, unknown.Names)
+	require.Equal(t, "[AddLabels]", holder.Perms.String())
+	require.NotNil(t, json.Unmarshal([]byte(`{"Perms":-1}`), &holder))
+	require.NotNil(t, json.Unmarshal([]byte(`{"Perms":{}}`), &holder))
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"path"
+	"testing"
+)
+
+func TestAttributes(t *testing.T) {
+	require.Equal(t, []goenum.Attribute{{Name: "code", Value: int64(500)}, {Name: "desc", Value: "网络错误"}},
+		goenum.Attributes(NetworkError))
+	require.Equal(t, []goenum.Attribute{{Name: "isFinal", Value: true}}, goenum.Attributes(TradeDelivered))
+	require.Equal(t, []goenum.Attribute{{Name: "basePath", Value: "/issues/"}, {Name: "perms", Value: []any{"AddLabels", "AddTopic"}}},
+		goenum.Attributes(Issues))
+	require.Nil(t, goenum.Attributes(Red))
+}
+
+func TestExport(t *testing.T) {
+	pkgPaths := []string{"github.com/lvyahui8/goenum/internal"}
+	dirs := map[string]string{
+		"typescript": "github.com/lvyahui8/goenum",
+		"python":     "github_com/lvyahui8/goenum",
+		"java":       "com/example/github_com/lvyahui8/goenum/internal",
+	}
+	for _, lang := range []string{"typescript", "python", "java"} {
+		files, err := goenum.Export(goenum.ExportOptions{Lang: lang, PkgPaths: pkgPaths, JavaPackage: "com.example"})
+		require.Nil(t, err)
+		for _, f := range files {
+			require.Equal(t, dirs[lang], path.Dir(f.Path))
+			requireGolden(t, "export/"+lang+"/"+path.Base(f.Path), f.Content)
+		}
+	}
+	files, err := goenum.Export(goenum.ExportOptions{Lang: "java", PkgPaths: pkgPaths})
+	require.Nil(t, err)
+	require.Len(t, files, 7)
+	require.Equal(t, "github_com/lvyahui8/goenum/internal/ColorEnum.java", files[0].Path)
+	require.Contains(t, string(files[0].Content), "package github_com.lvyahui8.goenum.internal;")
+	files, err = goenum.Export(goenum.ExportOptions{Lang: "ts", PkgPaths: []string{"github.com/lvyahui8/goenum/internal/pkga"}})
+	require.Nil(t, err)
+	require.Len(t, files, 0) // pkga没有被当前包导入，没有注册枚举
+	_, err = goenum.Export(goenum.ExportOptions{Lang: "rust"})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"bytes"
+	"flag"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEnumFlag(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		state := goenum.Flag(fs, "state", TradeCreated, "trade state")
+		require.Nil(t, fs.Parse([]string{"--state=pAiD"}))
+		require.True(t, state.Equals(TradePaid))
+		require.Equal(t, "Paid", fs.Lookup("state").Value.String())
+		require.Equal(t, TradePaid, fs.Lookup("state").Value.(flag.Getter).Get())
+	})
+	t.Run("Default", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		var state TradeState
+		goenum.FlagVar(fs, &state, "state", TradeShipped, "trade state")
+		require.Nil(t, fs.Parse(nil))
+		require.True(t, state.Equals(TradeShipped))
+		require.Equal(t, "Shipped", fs.Lookup("state").DefValue)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		goenum.Flag(fs, "state", TradeCreated, "trade state")
+		err := fs.Parse([]string{"--state=Unknown"})
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "allowed: Created, Failed, Paid, Shipped, Delivered")
+	})
+	t.Run("PtrEnum", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		var color *ColorEnum
+		fs.Var(goenum.NewEnumFlag(&color), "color", "color")
+		require.Equal(t, "", fs.Lookup("color").Value.String())
+		require.Nil(t, fs.Parse([]string{"-color", "yellow"}))
+		require.True(t, color.Equals(Yellow))
+	})
+	t.Run("Usage", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		out := &bytes.Buffer{}
+		fs.SetOutput(out)
+		goenum.Flag(fs, "state", TradeCreated, "trade state")
+		fs.PrintDefaults()
+		require.Contains(t, out.String(), "trade state (one of: Created, Failed, Paid, Shipped, Delivered)")
+		require.Equal(t, "(one of: Red, Yellow)", goenum.FlagUsage[*ColorEnum](""))
+	})
+}
+
+func TestEnumSetFlag(t *testing.T) {
+	t.Run("Parse", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		perms := goenum.NewUnsafeEnumSet[Permission]()
+		perms.Add(ViewMergeRequest)
+		goenum.FlagSetVar[Permission](fs, perms, "perms", "permissions")
+		require.Equal(t, "ViewMergeRequest", fs.Lookup("perms").DefValue)
+		require.Nil(t, fs.Parse([]string{"--perms=addLabels, AddTopic", "--perms", "DeleteMergeRequest"}))
+		require.Equal(t, "[AddLabels,AddTopic,DeleteMergeRequest]", perms.String())
+		require.Equal(t, "AddLabels,AddTopic,DeleteMergeRequest", fs.Lookup("perms").Value.String())
+		require.Equal(t, perms, fs.Lookup("perms").Value.(flag.Getter).Get())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		perms := goenum.NewUnsafeEnumSet[Permission]()
+		f := goenum.NewEnumSetFlag[Permission](perms)
+		err := f.Set("AddLabels,Foo,Bar")
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), `"Foo", "Bar"`)
+		require.True(t, perms.IsEmpty())
+	})
+}
+
+func TestCompleteFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	goenum.Flag(fs, "state", TradeCreated, "trade state")
+	goenum.FlagSetVar[Permission](fs, goenum.NewUnsafeEnumSet[Permission](), "perms", "permissions")
+	fs.String("name", "", "name")
+	require.Equal(t, []string{"--state=Failed"}, goenum.CompleteFlag(fs, "--state=f"))
+	require.Equal(t, []string{"-state=Created", "-state=Failed", "-state=Paid", "-state=Shipped", "-state=Delivered"},
+		goenum.CompleteFlag(fs, "-state="))
+	require.Equal(t, []string{"--perms=AddLabels,AddTopic"}, goenum.CompleteFlag(fs, "--perms=AddLabels,add"))
+	require.Equal(t, []string{"--perms=ViewMergeRequest"}, goenum.CompleteFlag(fs, "--perms=v"))
+	require.Nil(t, goenum.CompleteFlag(fs, "--name=a"))
+	require.Nil(t, goenum.CompleteFlag(fs, "--unknown=a"))
+	require.Nil(t, goenum.CompleteFlag(fs, "--state"))
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	for _, c := range []struct {
+		format   string
+		arg      goenum.EnumDefinition
+		expected string
+	}{
+		{"%s", TradePaid, "Paid"},
+		{"%v", TradePaid, "Paid"},
+		{"%q", TradePaid, `"Paid"`},
+		{"%d", TradePaid, "2"},
+		{"%+v", TradePaid, "internal.TradeState(Paid#2)"},
+		{"%#v", TradePaid, `goenum.MustValueOf[internal.TradeState]("Paid")`},
+		{"%-6s|", TradePaid, "Paid  |"},
+		{"%03d", TradePaid, "002"},
+		{"%x", TradePaid, "%!x(internal.TradeState=Paid)"},
+		// 组合指针
+		{"%v", Yellow, "Yellow"},
+		{"%d", Yellow, "1"},
+		{"%+v", Yellow, "*internal.ColorEnum(Yellow#1)"},
+		{"%#v", Yellow, `goenum.MustValueOf[*internal.ColorEnum]("Yellow")`},
+		{"%#v", TradeState{}, "goenum.Enum{}"},
+	} {
+		// 枚举自身实现了fmt.Formatter，与Formatted的结果相同
+		require.Equal(t, c.expected, fmt.Sprintf(c.format, c.arg), c.format)
+		require.Equal(t, c.expected, fmt.Sprintf(c.format, goenum.Formatted(c.arg)), c.format)
+	}
+	require.Implements(t, (*fmt.Formatter)(nil), TradePaid)
+	require.Implements(t, (*fmt.Formatter)(nil), Yellow)
+	// 结构体字段中的枚举
+	require.Equal(t, "{Paid 2}", fmt.Sprintf("%v", struct {
+		State TradeState
+		Count int
+	}{TradePaid, 2}))
+	// 表达式可以求值为同一个成员
+	require.Equal(t, TradePaid, goenum.MustValueOf[TradeState]("Paid"))
+}
+
+// statusCode 自定义String的枚举
+type statusCode struct {
+	goenum.Enum
+	code int
+}
+
+func (c statusCode) String() string {
+	return c.Name() + "(" + strconv.Itoa(c.code) + ")"
+}
+
+// Format 提升的Enum.Format看不到statusCode.String，转发给Formatted
+func (c statusCode) Format(f fmt.State, verb rune) {
+	goenum.Formatted(c).Format(f, verb)
+}
+
+// plainCode 自定义String但没有实现Format的枚举
+type plainCode struct {
+	goenum.Enum
+}
+
+func (c plainCode) String() string {
+	return "code " + c.Name()
+}
+
+func TestFormat_Stringer(t *testing.T) {
+	reg := goenum.NewRegistry(nil)
+	ok := goenum.NewEnumIn[statusCode](reg, "OK", statusCode{code: 200})
+	require.Equal(t, "OK(200)", ok.String())
+	require.Equal(t, "OK(200) OK(200) \"OK(200)\" 0", fmt.Sprintf("%v %s %q %d", ok, ok, ok, ok))
+	require.Equal(t, "OK(200) 0", fmt.Sprintf("%v %d", goenum.Formatted(ok), goenum.Formatted(ok)))
+	// 自定义注册表的成员不能用MustValueOf求值
+	require.Equal(t, `goenum.Enum{name:"OK", _type:"internal.statusCode", index:0}`, fmt.Sprintf("%#v", ok))
+	set := goenum.NewUnsafeEnumSetIn[statusCode](reg)
+	set.Add(ok)
+	require.Equal(t, "[OK(200)] [0]", fmt.Sprintf("%v %d", set, set))
+
+	plain := goenum.NewEnumIn[plainCode](reg, "Plain")
+	require.Equal(t, "Plain code Plain", fmt.Sprintf("%v %s", plain, plain.String()))
+}
+
+func TestFormat_EnumSet(t *testing.T) {
+	set := goenum.NewUnsafeEnumSet[TradeState]()
+	set.Add(TradeCreated)
+	set.Add(TradeShipped)
+	require.Equal(t, "[Created,Shipped]", fmt.Sprintf("%v", set))
+	require.Equal(t, "[0,3]", fmt.Sprintf("%d", set))
+	require.Equal(t, `["Created","Shipped"]`, fmt.Sprintf("%q", set))
+	require.Equal(t, "[internal.TradeState(Created#0),internal.TradeState(Shipped#3)]", fmt.Sprintf("%+v", set))
+	require.Equal(t, `[goenum.MustValueOf[internal.TradeState]("Created"),goenum.MustValueOf[internal.TradeState]("Shipped")]`, fmt.Sprintf("%#v", set))
+	require.Equal(t, "[]", fmt.Sprintf("%v", goenum.NewUnsafeEnumSet[TradeState]()))
+	// 宽度和标志作用于每个成员，与fmt格式化切片的方式相同
+	require.Equal(t, "[Created ,Shipped ]", fmt.Sprintf("%-8s", set))
+	require.Equal(t, "[   Created,   Shipped]", fmt.Sprintf("%10v", set))
+	require.Equal(t, "[000,003]", fmt.Sprintf("%03d", set))
+}
+
+func TestMustValueOf(t *testing.T) {
+	require.Equal(t, TradePaid, goenum.MustValueOf[TradeState]("Paid"))
+	require.PanicsWithValue(t, `goenum: "Unknown" is not a member of internal.TradeState`, func() {
+		goenum.MustValueOf[TradeState]("Unknown")
+	})
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"flag"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update 更新golden文件: go test ./internal -update
+var update = flag.Bool("update", false, "update golden files")
+
+// requireGolden Compare data with the golden file testdata/name, or rewrite the golden file with -update
+func requireGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+	file := filepath.Join("testdata", name)
+	if *update {
+		require.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.Nil(t, os.WriteFile(file, data, 0644))
+	}
+	expected, err := os.ReadFile(file)
+	require.Nil(t, err)
+	require.Equal(t, string(expected), string(data))
+}
@@ -0,0 +1,67 @@
+//go:build go1.23
+
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"slices"
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+	t.Run("All", func(t *testing.T) {
+		require.Equal(t, goenum.Values[TradeState](), slices.Collect(goenum.All[TradeState]()))
+		var first []TradeState
+		for s := range goenum.All[TradeState]() {
+			first = append(first, s)
+			break
+		}
+		require.Equal(t, []TradeState{TradeCreated}, first)
+	})
+	t.Run("FilterMap", func(t *testing.T) {
+		finals := goenum.Filter(goenum.All[TradeState](), TradeState.IsFinal)
+		require.Equal(t, []string{"Failed", "Delivered"}, slices.Collect(goenum.Map(finals, TradeState.Name)))
+	})
+	t.Run("EnumSet", func(t *testing.T) {
+		set := goenum.NewUnsafeEnumSet[Permission]()
+		set.Add(DeleteMergeRequest)
+		set.Add(AddLabels)
+		set.Add(ViewMergeRequest)
+		require.Equal(t, []Permission{AddLabels, ViewMergeRequest, DeleteMergeRequest}, slices.Collect(set.All()))
+		require.Equal(t, []Permission{DeleteMergeRequest, ViewMergeRequest, AddLabels}, slices.Collect(set.Backward()))
+		for p := range set.Backward() {
+			require.Equal(t, DeleteMergeRequest, p)
+			break
+		}
+	})
+	t.Run("CustomRegistry", func(t *testing.T) {
+		reg := goenum.NewRegistry(nil)
+		free := goenum.NewEnumIn[Plan](reg, "Free")
+		pro := goenum.NewEnumIn[Plan](reg, "Pro")
+		set := goenum.NewUnsafeEnumSetIn[Plan](reg)
+		set.Add(free)
+		set.Add(pro)
+		require.Equal(t, []Plan{free, pro}, slices.Collect(set.All()))
+		require.Equal(t, []Plan{pro, free}, slices.Collect(set.Backward()))
+		m := goenum.NewEnumMapIn[Plan, int](reg)
+		m.Put(pro, 2)
+		for k, v := range m.All() {
+			require.Equal(t, pro, k)
+			require.Equal(t, 2, v)
+		}
+	})
+	t.Run("EnumMap", func(t *testing.T) {
+		m := goenum.NewEnumMap[TradeState, int]()
+		m.Put(TradePaid, 2)
+		m.Put(TradeCreated, 0)
+		var keys []TradeState
+		var values []int
+		for k, v := range m.All() {
+			keys = append(keys, k)
+			values = append(values, v)
+		}
+		require.Equal(t, []TradeState{TradeCreated, TradePaid}, keys)
+		require.Equal(t, []int{0, 2}, values)
+	})
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLock(t *testing.T) {
+	lock := goenum.Snapshot("github.com/lvyahui8/goenum/internal")
+	data, err := json.Marshal(lock)
+	require.Nil(t, err)
+	// 从lockfile读取的数值为float64，不应被视为属性变化
+	old := &goenum.Lock{}
+	require.Nil(t, json.Unmarshal(data, old))
+	report := goenum.CompareLock(old, lock)
+	require.Empty(t, report.Changes)
+	require.False(t, report.Breaking())
+	require.Equal(t, "no changes\n", report.String())
+	require.Empty(t, goenum.Snapshot("example.com/none").Types)
+}
+
+func TestCompareLock(t *testing.T) {
+	old := &goenum.Lock{Types: []goenum.LockType{
+		{Name: "a.Status", Members: []goenum.LockMember{
+			{Name: "Created", Ordinal: 0, Attributes: []goenum.Attribute{{Name: "code", Value: float64(1)}}},
+			{Name: "Paid", Ordinal: 1},
+			{Name: "Closed", Ordinal: 2},
+			{Name: "Failed", Ordinal: 3},
+		}},
+		{Name: "a.Removed", Members: []goenum.LockMember{{Name: "X"}}},
+	}}
+	current := &goenum.Lock{Types: []goenum.LockType{
+		{Name: "a.Status", Members: []goenum.LockMember{
+			{Name: "Created", Ordinal: 0, Attributes: []goenum.Attribute{{Name: "code", Value: int64(2)}, {Name: "desc", Value: "new"}}},
+			{Name: "Paying", Ordinal: 1},
+			{Name: "Paid", Ordinal: 2},
+			{Name: "Refunded", Ordinal: 3},
+			{Name: "Failed", Ordinal: 4},
+		}},
+		{Name: "a.Added", Members: []goenum.LockMember{{Name: "Y"}}},
+	}}
+	report := goenum.CompareLock(old, current)
+	require.True(t, report.Breaking())
+	require.Equal(t, `BREAKING a.Status.Created: attribute code changed from 1 to 2
+a.Status.Created: attribute desc changed from none to "new"
+BREAKING a.Status.Paid: ordinal changed from 1 to 2
+BREAKING a.Status.Closed: member removed, its ordinal was 2
+BREAKING a.Status.Failed: ordinal changed from 3 to 4
+a.Status.Paying: member added with ordinal 1
+a.Status.Refunded: member added with ordinal 3
+BREAKING a.Removed: type removed
+a.Added: type added
+`, report.String())
+
+	// 同一序数上的新名称视为重命名
+	renamed := &goenum.Lock{Types: []goenum.LockType{{Name: "a.Status", Members: []goenum.LockMember{
+		{Name: "Created", Ordinal: 0, Attributes: []goenum.Attribute{{Name: "code", Value: 1}}},
+		{Name: "Paid", Ordinal: 1},
+		{Name: "Cancelled", Ordinal: 2},
+		{Name: "Failed", Ordinal: 3},
+	}}}}
+	report = goenum.CompareLock(&goenum.Lock{Types: old.Types[:1]}, renamed)
+	require.Equal(t, []goenum.LockChange{{Kind: goenum.LockMemberRenamed, Type: "a.Status", Member: "Closed",
+		Old: "Closed", New: "Cancelled", Breaking: true}}, report.Changes)
+	data, err := json.Marshal(report)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"changes":[{"kind":"member_renamed","type":"a.Status","member":"Closed","old":"Closed","new":"Cancelled","breaking":true}]}`, string(data))
+}
+
+func TestCompareLock_SameNamedTypes(t *testing.T) {
+	old := &goenum.Lock{Types: []goenum.LockType{
+		{Name: "status.Status", PkgPath: "example.com/order/status", Members: []goenum.LockMember{{Name: "Paid"}}},
+		{Name: "status.Status", PkgPath: "example.com/ship/status", Members: []goenum.LockMember{{Name: "Shipped"}}},
+	}}
+	current := &goenum.Lock{Types: []goenum.LockType{
+		{Name: "status.Status", PkgPath: "example.com/ship/status", Members: []goenum.LockMember{{Name: "Shipped"}}},
+		{Name: "status.Status", PkgPath: "example.com/order/status", Members: []goenum.LockMember{{Name: "Paid"}}},
+	}}
+	// 同名类型不应互相覆盖，顺序变化也不是变更
+	require.Empty(t, goenum.CompareLock(old, current).Changes)
+
+	current.Types = current.Types[:1]
+	current.Types[0].Members = append(current.Types[0].Members, goenum.LockMember{Name: "Delivered", Ordinal: 1})
+	require.Equal(t, `BREAKING example.com/order/status.Status: type removed
+example.com/ship/status.Status.Delivered: member added with ordinal 1
+`, goenum.CompareLock(old, current).String())
+}
@@ -0,0 +1,90 @@
+//go:build go1.21
+
+package internal
+
+import (
+	"bytes"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+// newJSONLogger 输出不含时间与级别的JSON日志
+func newJSONLogger(buf *bytes.Buffer, wrap func(slog.Handler) slog.Handler) *slog.Logger {
+	var h slog.Handler = slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	if wrap != nil {
+		h = wrap(h)
+	}
+	return slog.New(h)
+}
+
+func TestLogValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	set := goenum.NewUnsafeEnumSet[TradeState]()
+	set.Add(TradePaid)
+	set.Add(TradeShipped)
+	newJSONLogger(buf, nil).Info("trade", "state", TradePaid, "color", Red, "states", set)
+	require.JSONEq(t, `{"msg":"trade",
+		"state":{"name":"Paid","ordinal":2,"type":"internal.TradeState"},
+		"color":{"name":"Red","ordinal":0,"type":"*internal.ColorEnum"},
+		"states":{"type":"internal.TradeState","names":["Paid","Shipped"]}}`, buf.String())
+}
+
+func TestLogHandler(t *testing.T) {
+	goenum.MarkSensitive[ReverseState]()
+	goenum.MarkSensitive[ErrorCode]("desc")
+	t.Cleanup(goenum.UnmarkSensitive[ReverseState])
+	t.Cleanup(goenum.UnmarkSensitive[ErrorCode])
+	set := goenum.NewUnsafeEnumSet[TradeState]()
+	set.Add(TradePaid)
+	reverses := goenum.NewUnsafeEnumSet[ReverseState]()
+	reverses.Add(ReverseFailed)
+
+	t.Run("Compact", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := newJSONLogger(buf, func(h slog.Handler) slog.Handler {
+			return goenum.NewLogHandler(h, &goenum.LogHandlerOptions{Compact: true})
+		})
+		logger.With("code", Success).WithGroup("trade").Info("trade",
+			"state", TradePaid, "states", set, "reverse", ReverseFailed,
+			slog.Group("nested", "color", Yellow, "reverses", reverses))
+		require.JSONEq(t, `{"msg":"trade","code":"Success",
+			"trade":{"state":"Paid","states":"[Paid]","reverse":"[REDACTED]",
+				"nested":{"color":"Yellow","reverses":"[REDACTED]"}}}`, buf.String())
+	})
+	t.Run("Attributes", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := newJSONLogger(buf, func(h slog.Handler) slog.Handler {
+			return goenum.NewLogHandler(h, &goenum.LogHandlerOptions{Attributes: true})
+		})
+		logger.Info("failed", "code", NetworkError, "state", TradeDelivered, "reverse", ReverseFailed)
+		require.JSONEq(t, `{"msg":"failed",
+			"code":{"name":"NetworkError","ordinal":2,"type":"internal.Code","attributes":{"code":500,"desc":"[REDACTED]"}},
+			"state":{"name":"Delivered","ordinal":4,"type":"internal.TradeState","attributes":{"isFinal":true}},
+			"reverse":"[REDACTED]"}`, buf.String())
+	})
+	t.Run("WithoutHandler", func(t *testing.T) {
+		// 整个类型敏感时，不使用LogHandler也会被脱敏
+		buf := &bytes.Buffer{}
+		newJSONLogger(buf, nil).Info("reverse", "reverse", ReverseFailed, "reverses", reverses)
+		require.JSONEq(t, `{"msg":"reverse","reverse":"[REDACTED]","reverses":"[REDACTED]"}`, buf.String())
+	})
+}
+
+func TestUnmarkSensitive(t *testing.T) {
+	goenum.MarkSensitive[ReverseState]()
+	require.Equal(t, goenum.Redacted, ReverseFailed.LogValue().String())
+	goenum.UnmarkSensitive[ReverseState]()
+	require.Equal(t, slog.KindGroup, ReverseFailed.LogValue().Kind())
+	set := goenum.NewUnsafeEnumSet[ReverseState]()
+	set.Add(ReverseFailed)
+	require.Equal(t, slog.KindGroup, set.LogValue().Kind())
+}
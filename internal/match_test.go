@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func label(text string) func(TradeState) string {
+	return func(TradeState) string { return text }
+}
+
+var tradeLabel = goenum.NewMatcher[TradeState, string]().
+	Case(TradeCreated, label("awaiting payment")).
+	Case(TradeFailed, label("failed")).
+	Case(TradePaid, label("paid")).
+	Case(TradeShipped, label("shipped")).
+	Case(TradeDelivered, label("delivered")).
+	MustBeExhaustive()
+
+func TestMatcher(t *testing.T) {
+	require.Equal(t, "paid", tradeLabel.Apply(TradePaid))
+	require.Equal(t, "delivered", tradeLabel.Apply(TradeDelivered))
+	require.Nil(t, tradeLabel.Check())
+
+	partial := goenum.NewMatcher[TradeState, string]().
+		Case(TradePaid, label("paid")).
+		Case(TradeShipped, label("shipped"))
+	err := partial.Check()
+	var nonExhaustive *goenum.NonExhaustiveError
+	require.True(t, errors.As(err, &nonExhaustive))
+	require.Equal(t, []string{"Created", "Failed", "Delivered"}, nonExhaustive.Missing)
+	require.Equal(t, "goenum: Match of internal.TradeState misses Created, Failed, Delivered", err.Error())
+	require.Equal(t, "paid", partial.Apply(TradePaid))
+	require.PanicsWithError(t, "goenum: Match of internal.TradeState misses Created", func() {
+		partial.Apply(TradeCreated)
+	})
+	require.PanicsWithError(t, err.Error(), func() {
+		goenum.NewMatcher[TradeState, string]().Case(TradePaid, label("paid")).Case(TradeShipped, label("shipped")).MustBeExhaustive()
+	})
+
+	// 默认分支处理没有case的成员，但不满足穷举检查
+	partial.Default(label("other"))
+	require.Equal(t, "other", partial.Apply(TradeCreated))
+	partial.Exhaustive()
+	require.PanicsWithError(t, err.Error(), func() { partial.Apply(TradePaid) })
+
+	require.PanicsWithValue(t, `goenum: duplicate case "Paid" of internal.TradeState`, func() {
+		goenum.NewMatcher[TradeState, string]().Case(TradePaid, label("paid")).Case(TradePaid, label("paid"))
+	})
+	require.Panics(t, func() {
+		goenum.NewMatcher[TradeState, string]().Case(TradeState{}, label("zero"))
+	})
+}
+
+func TestMatcher_Ptr(t *testing.T) {
+	hex := goenum.NewMatcher[*ColorEnum, string]().
+		Case(Red, func(*ColorEnum) string { return "#f00" }).
+		Case(Yellow, func(*ColorEnum) string { return "#ff0" }).
+		MustBeExhaustive()
+	require.Equal(t, "#ff0", hex.Apply(Yellow))
+}
+
+func TestMatcherIn(t *testing.T) {
+	reg := goenum.NewRegistry(nil)
+	free := goenum.NewEnumIn[Plan](reg, "Free")
+	pro := goenum.NewEnumIn[Plan](reg, "Pro")
+	quota := goenum.NewMatcherIn[Plan, int](reg).
+		Case(free, func(Plan) int { return 1 }).
+		Exhaustive()
+	require.PanicsWithError(t, "goenum: Match of internal.Plan misses Pro", func() { quota.Apply(free) })
+	quota = goenum.NewMatcherIn[Plan, int](reg).
+		Case(free, func(Plan) int { return 1 }).
+		Case(pro, func(Plan) int { return 10 }).
+		MustBeExhaustive()
+	require.Equal(t, 10, quota.Apply(pro))
+	// 默认注册表的Matcher不接受其他注册表的成员
+	require.Panics(t, func() { goenum.NewMatcher[Plan, int]().Case(free, func(Plan) int { return 1 }) })
+}
+
+func TestMatch(t *testing.T) {
+	final := func(s TradeState) bool {
+		return goenum.Match[TradeState, bool](s).
+			Case(TradeCreated, func(TradeState) bool { return false }).
+			Case(TradeFailed, func(TradeState) bool { return true }).
+			Case(TradePaid, func(TradeState) bool { return false }).
+			Case(TradeShipped, func(TradeState) bool { return false }).
+			Case(TradeDelivered, func(TradeState) bool { return true }).
+			Exhaustive()
+	}
+	for _, s := range goenum.Values[TradeState]() {
+		require.Equal(t, s.IsFinal(), final(s), s.Name())
+	}
+	require.Panics(t, func() {
+		goenum.Match[TradeState, bool](TradePaid).Case(TradePaid, func(TradeState) bool { return false }).Exhaustive()
+	})
+	r, ok := goenum.Match[TradeState, string](TradePaid).Case(TradePaid, label("paid")).Result()
+	require.True(t, ok)
+	require.Equal(t, "paid", r)
+	_, ok = goenum.Match[TradeState, string](TradeCreated).Case(TradePaid, label("paid")).Result()
+	require.False(t, ok)
+}
+
+// switchLabel 与tradeLabel等价的switch语句
+func switchLabel(s TradeState) string {
+	switch s {
+	case TradeCreated:
+		return "awaiting payment"
+	case TradeFailed:
+		return "failed"
+	case TradePaid:
+		return "paid"
+	case TradeShipped:
+		return "shipped"
+	default:
+		return "delivered"
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	b.Run("switch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = switchLabel(TradeDelivered)
+		}
+	})
+	b.Run("Matcher", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = tradeLabel.Apply(TradeDelivered)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestOrdinalHelpers(t *testing.T) {
+	t.Run("FirstLast", func(t *testing.T) {
+		first, valid := goenum.First[TradeState]()
+		require.True(t, valid)
+		require.Equal(t, TradeCreated, first)
+		last, valid := goenum.Last[TradeState]()
+		require.True(t, valid)
+		require.Equal(t, TradeDelivered, last)
+		_, valid = goenum.First[Plan]()
+		require.False(t, valid)
+		_, valid = goenum.Last[Plan]()
+		require.False(t, valid)
+	})
+	t.Run("NextPrev", func(t *testing.T) {
+		next, valid := goenum.Next(TradePaid)
+		require.True(t, valid)
+		require.Equal(t, TradeShipped, next)
+		_, valid = goenum.Next(TradeDelivered)
+		require.False(t, valid)
+		prev, valid := goenum.Prev(TradePaid)
+		require.True(t, valid)
+		require.Equal(t, TradeFailed, prev)
+		_, valid = goenum.Prev(TradeCreated)
+		require.False(t, valid)
+		require.Equal(t, TradeCreated, goenum.NextWrap(TradeDelivered))
+		require.Equal(t, TradeDelivered, goenum.PrevWrap(TradeCreated))
+		require.Equal(t, Yellow, goenum.NextWrap(Red))
+	})
+	t.Run("Range", func(t *testing.T) {
+		require.Equal(t, []TradeState{TradePaid, TradeShipped, TradeDelivered}, goenum.ValuesBetween(TradePaid, TradeDelivered))
+		require.Equal(t, []TradeState{TradePaid}, goenum.ValuesBetween(TradePaid, TradePaid))
+		require.Nil(t, goenum.ValuesBetween(TradeShipped, TradePaid))
+	})
+	t.Run("Between", func(t *testing.T) {
+		require.True(t, goenum.Between(TradeShipped, TradePaid, TradeDelivered))
+		require.True(t, goenum.Between(TradePaid, TradePaid, TradeDelivered))
+		require.False(t, goenum.Between(TradeCreated, TradePaid, TradeDelivered))
+	})
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Plan 仅在测试的独立注册表中注册
+type Plan struct {
+	goenum.Enum
+	Quota int
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("Isolated", func(t *testing.T) {
+		reg := goenum.NewRegistry(nil)
+		free := goenum.NewEnumIn[Plan](reg, "Free", Plan{Quota: 1})
+		pro := goenum.NewEnumIn[Plan](reg, "Pro", Plan{Quota: 10})
+		require.Equal(t, 0, free.Ordinal())
+		require.Equal(t, 1, pro.Ordinal())
+		require.Equal(t, []Plan{free, pro}, goenum.ValuesIn[Plan](reg))
+		require.Equal(t, 2, goenum.SizeIn[Plan](reg))
+		v, valid := goenum.ValueOfIn[Plan](reg, "Pro")
+		require.True(t, valid)
+		require.Equal(t, 10, v.Quota)
+		v, valid = goenum.ValueOfIgnoreCaseIn[Plan](reg, "free")
+		require.True(t, valid)
+		require.True(t, v.Equals(free))
+		require.Len(t, goenum.GetEnumMapIn[Plan](reg), 2)
+		// 默认注册表不受影响
+		require.Nil(t, goenum.Values[Plan]())
+		require.False(t, goenum.IsValidEnum[Plan]("Free"))
+		// 同名成员可以注册到另一个注册表
+		other := goenum.NewRegistry(nil)
+		require.Equal(t, 0, goenum.NewEnumIn[Plan](other, "Free").Ordinal())
+		require.Panics(t, func() {
+			goenum.NewEnumIn[Plan](reg, "Free")
+		})
+	})
+	t.Run("Layered", func(t *testing.T) {
+		tenant := goenum.NewRegistry(goenum.DefaultRegistry())
+		require.Equal(t, goenum.DefaultRegistry(), tenant.Parent())
+		require.Equal(t, goenum.Values[TradeState](), goenum.ValuesIn[TradeState](tenant))
+		refunded := goenum.NewEnumIn[TradeState](tenant, "Refunded")
+		require.Equal(t, goenum.Size[TradeState](), refunded.Ordinal())
+		require.Equal(t, append(goenum.Values[TradeState](), refunded), goenum.ValuesIn[TradeState](tenant))
+		// 子注册表回退到父注册表，反之不成立
+		require.True(t, goenum.IsValidEnumIn[TradeState](tenant, "Paid"))
+		require.True(t, goenum.IsValidEnumIn[TradeState](tenant, "Refunded"))
+		require.False(t, goenum.IsValidEnum[TradeState]("Refunded"))
+		// 父注册表中已存在的成员不能在子注册表中重复注册
+		require.Panics(t, func() {
+			goenum.NewEnumIn[TradeState](tenant, "Paid")
+		})
+	})
+	t.Run("Dynamic", func(t *testing.T) {
+		tenant := goenum.NewRegistry(goenum.DefaultRegistry())
+		events, cancel := goenum.WatchIn[TradeState](tenant)
+		defer cancel()
+		states := goenum.NewDynamicTypeIn[TradeState](tenant, nil, nil)
+		res, err := states.LoadJSON(strings.NewReader(`{"members":[{"name":"Closed"}]}`))
+		require.Nil(t, err)
+		require.Equal(t, goenum.Size[TradeState](), res[0].Ordinal())
+		require.Equal(t, []string{"Closed"}, goenum.EnumNames((<-events).Added[0].(TradeState)))
+		require.False(t, goenum.IsValidEnum[TradeState]("Closed"))
+
+		_, err = states.LoadJSON(strings.NewReader(`{"members":[{"name":"Paid"}]}`))
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), `duplicate enum "Paid"`)
+		_, err = states.ReloadJSON(strings.NewReader(`{"members":[]}`))
+		require.Nil(t, err)
+		require.True(t, tenant.IsDeprecated(res[0]))
+		require.False(t, tenant.IsDeprecated(TradePaid))
+		require.False(t, goenum.IsDeprecated(res[0]))
+
+		// 子注册表扩展了TradeState，父注册表不能再加载新成员
+		_, err = goenum.NewDynamicType[TradeState](nil, nil).LoadJSON(strings.NewReader(`{"members":[{"name":"Refund"}]}`))
+		require.EqualError(t, err, "goenum: invalid enum definition: internal.TradeState is extended by a child registry, "+
+			"its members must be registered before the children's")
+		require.False(t, goenum.IsValidEnum[TradeState]("Refund"))
+	})
+	t.Run("Checkpoint", func(t *testing.T) {
+		reg := goenum.NewRegistry(nil)
+		free := goenum.NewEnumIn[Plan](reg, "Free")
+		cp := reg.Checkpoint()
+		goenum.NewEnumIn[Plan](reg, "Pro")
+		cp.Accept()
+		require.Nil(t, cp.Restore())
+		require.Equal(t, []Plan{free}, goenum.ValuesIn[Plan](reg))
+		// 持有者之外的修改使恢复失败，不会被静默丢弃
+		cp = reg.Checkpoint()
+		goenum.NewEnumIn[Plan](reg, "Pro")
+		cp.Accept()
+		goenum.NewEnumIn[Plan](reg, "Team")
+		require.EqualError(t, cp.Restore(), "goenum: the registry has been changed by others since the checkpoint, it is not restored")
+		require.Equal(t, 3, goenum.SizeIn[Plan](reg))
+		// 注册失败不影响恢复
+		cp = reg.Checkpoint()
+		require.Panics(t, func() { goenum.NewEnumIn[Plan](reg, "Free") })
+		require.Nil(t, cp.Restore())
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		// 注册过程中并发读取，每次读取都看到一致的快照，go test -race
+		reg := goenum.NewRegistry(nil)
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					for ordinal, p := range goenum.ValuesViewIn[Plan](reg) {
+						if p.Ordinal() != ordinal {
+							panic("inconsistent snapshot")
+						}
+					}
+				}
+			}()
+		}
+		for i := 0; i < 2000; i++ {
+			p := goenum.NewEnumIn[Plan](reg, "P"+strconv.Itoa(i))
+			if i%100 == 0 {
+				// 新成员对后续读取立即可见
+				require.True(t, goenum.IsValidEnumIn[Plan](reg, p.Name()))
+			}
+		}
+		close(done)
+		wg.Wait()
+		require.Equal(t, 2000, goenum.SizeIn[Plan](reg))
+		v, valid := goenum.ValueOfIn[Plan](reg, "P1999")
+		require.True(t, valid)
+		require.Equal(t, 1999, v.Ordinal())
+	})
+}
+
+// BenchmarkNewEnumIn 连续注册的开销与已注册的成员数量无关
+//
+//	go test ./internal -run=^$ -bench=BenchmarkNewEnumIn -benchmem
+func BenchmarkNewEnumIn(b *testing.B) {
+	reg := goenum.NewRegistry(nil)
+	for i := 0; i < b.N; i++ {
+		goenum.NewEnumIn[Plan](reg, "P"+strconv.Itoa(i))
+	}
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"encoding/json"
 	"github.com/lvyahui8/goenum"
+	"github.com/lvyahui8/goenum/goenumtest"
 	"github.com/stretchr/testify/require"
 	"reflect"
 	"testing"
@@ -63,12 +64,8 @@ func TestRoleBasic(t *testing.T) {
 
 func TestHelpers(t *testing.T) {
 	t.Run("NewEnum", func(t *testing.T) {
-		defer func() {
-			err := recover()
-			require.NotNil(t, err)
-			require.Equal(t, "Enum must be unique", err)
-		}()
-		_ = goenum.NewEnum[Role]("Owner")
+		_, err := goenumtest.NewEnum[Role](goenumtest.NewSandbox(t), "Owner")
+		require.EqualError(t, err, "Enum must be unique")
 	})
 	t.Run("ValueOf", func(t *testing.T) {
 		r, valid := goenum.ValueOf[Role]("Owner")
@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"encoding/json"
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func marshalSchema(t *testing.T, v any) []byte {
+	data, err := json.MarshalIndent(v, "", "  ")
+	require.Nil(t, err)
+	return append(data, '\n')
+}
+
+func TestJSONSchema(t *testing.T) {
+	require.Equal(t, `{"type":"string","enum":["Red","Yellow"]}`, string(mustMarshal(t, goenum.JSONSchema[*ColorEnum]())))
+	requireGolden(t, "schema/trade_state.json", marshalSchema(t, goenum.JSONSchema[TradeState]()))
+	requireGolden(t, "schema/error_code.json", marshalSchema(t, goenum.JSONSchema[ErrorCode]()))
+	requireGolden(t, "schema/permission_set.json", marshalSchema(t, goenum.EnumSetJSONSchema[Permission]()))
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	components := goenum.OpenAPIComponents(TradeOrder{}, &TradeItem{})
+	require.Len(t, components.Schemas, 7)
+	requireGolden(t, "schema/openapi_components.json", marshalSchema(t, components))
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	data, err := json.Marshal(v)
+	require.Nil(t, err)
+	return data
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"github.com/stretchr/testify/require"
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestFuncMap_HTML(t *testing.T) {
+	tmpl := htmltemplate.Must(htmltemplate.New("select").Funcs(goenum.FuncMap()).Parse(
+		`<select>{{range enumValues "internal.TradeState"}}` +
+			`<option value="{{.Name}}"{{if enumIs . $.State}} selected{{end}}>{{enumLabel .}}</option>` +
+			`{{end}}</select>`))
+	var buf strings.Builder
+	require.Nil(t, tmpl.Execute(&buf, map[string]any{"State": TradePaid}))
+	require.Equal(t, `<select><option value="Created">Created</option><option value="Failed">Failed</option>`+
+		`<option value="Paid" selected>Paid</option><option value="Shipped">Shipped</option>`+
+		`<option value="Delivered">Delivered</option></select>`, buf.String())
+}
+
+func TestFuncMap_Text(t *testing.T) {
+	perms := goenum.NewUnsafeEnumSet[Permission]()
+	perms.Add(AddLabels)
+	data := map[string]any{"State": TradeShipped, "Code": NetworkError, "Perms": perms}
+	for _, c := range []struct {
+		text     string
+		expected string
+	}{
+		{`{{if enumIs .State "Paid" "Shipped"}}in progress{{end}}`, "in progress"},
+		{`{{enumIs .State "Created"}}`, "false"},
+		{`{{enumAttr .State "isFinal"}}`, "false"},
+		{`{{enumLabel .Code "desc"}}`, "网络错误"},
+		{`{{join (enumNames "ReverseState") ","}}`, "Created,Failed,Refunded"},
+		{`{{(enumValueOf "TradeState" "delivered").Ordinal}}`, "4"},
+		{`{{enumIs .State (enumValueOf "TradeState" "Shipped")}}`, "true"},
+		{`{{setHas .Perms "AddLabels"}} {{setHas .Perms "AddTopic"}}`, "true false"},
+		{`{{range enumValues "ColorEnum"}}{{.}} {{end}}`, "Red Yellow "},
+	} {
+		tmpl := template.Must(template.New("").Funcs(goenum.FuncMap()).
+			Funcs(template.FuncMap{"join": strings.Join}).Parse(c.text))
+		var buf strings.Builder
+		require.Nil(t, tmpl.Execute(&buf, data), c.text)
+		require.Equal(t, c.expected, buf.String(), c.text)
+	}
+	for _, text := range []string{
+		`{{enumValues "Unknown"}}`,
+		`{{enumValueOf "TradeState" "Unknown"}}`,
+		`{{enumAttr .State "priority"}}`,
+		`{{enumLabel .State "priority"}}`,
+		`{{setHas .Perms 1}}`,
+	} {
+		tmpl := template.Must(template.New("").Funcs(goenum.FuncMap()).Parse(text))
+		require.NotNil(t, tmpl.Execute(&strings.Builder{}, data), text)
+	}
+}
@@ -0,0 +1,38 @@
+// Code generated by goenum export. DO NOT EDIT.
+
+package com.example.github_com.lvyahui8.goenum.internal;
+
+/**
+ * internal.Code
+ */
+public enum Code {
+    Success("Success", 0L, "成功"),
+    Failed("Failed", -1L, "未知异常"),
+    NetworkError("NetworkError", 500L, "网络错误"),
+    EncodeError("EncodeError", 600L, "编码错误"),
+    Member("Member", 2L, "支付服务"),
+    Trade("Trade", 2L, "交易服务"),
+    Delivery("Delivery", 2L, "履约服务");
+
+    private final String goName;
+    private final long code;
+    private final String desc;
+
+    Code(String goName, long code, String desc) {
+        this.goName = goName;
+        this.code = code;
+        this.desc = desc;
+    }
+
+    public String getGoName() {
+        return goName;
+    }
+
+    public long getCode() {
+        return code;
+    }
+
+    public String getDesc() {
+        return desc;
+    }
+}
@@ -0,0 +1,33 @@
+// Code generated by goenum export. DO NOT EDIT.
+
+package com.example.github_com.lvyahui8.goenum.internal;
+
+/**
+ * internal.Module
+ */
+public enum Module {
+    Issues("Issues", "/issues/", java.util.List.of("AddLabels", "AddTopic")),
+    MergeRequests("MergeRequests", "/merge/", java.util.List.of("ViewMergeRequest", "ApproveMergeRequest", "DeleteMergeRequest"));
+
+    private final String goName;
+    private final String basePath;
+    private final java.util.List<String> perms;
+
+    Module(String goName, String basePath, java.util.List<String> perms) {
+        this.goName = goName;
+        this.basePath = basePath;
+        this.perms = perms;
+    }
+
+    public String getGoName() {
+        return goName;
+    }
+
+    public String getBasePath() {
+        return basePath;
+    }
+
+    public java.util.List<String> getPerms() {
+        return perms;
+    }
+}
@@ -0,0 +1,30 @@
+// Code generated by goenum export. DO NOT EDIT.
+
+package com.example.github_com.lvyahui8.goenum.internal;
+
+/**
+ * internal.TradeState
+ */
+public enum TradeState {
+    Created("Created", false),
+    Failed("Failed", true),
+    Paid("Paid", false),
+    Shipped("Shipped", false),
+    Delivered("Delivered", true);
+
+    private final String goName;
+    private final boolean isFinal;
+
+    TradeState(String goName, boolean isFinal) {
+        this.goName = goName;
+        this.isFinal = isFinal;
+    }
+
+    public String getGoName() {
+        return goName;
+    }
+
+    public boolean isFinal() {
+        return isFinal;
+    }
+}
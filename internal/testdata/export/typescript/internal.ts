@@ -0,0 +1,70 @@
+// Code generated by goenum export. DO NOT EDIT.
+
+// *internal.ColorEnum
+export type ColorEnum = "Red" | "Yellow";
+
+export const ColorEnum = {
+  "Red": { name: "Red", ordinal: 0 },
+  "Yellow": { name: "Yellow", ordinal: 1 },
+} as const;
+
+// internal.Code
+export type Code = "Success" | "Failed" | "NetworkError" | "EncodeError" | "Member" | "Trade" | "Delivery";
+
+export const Code = {
+  "Success": { name: "Success", ordinal: 0, code: 0, desc: "成功" },
+  "Failed": { name: "Failed", ordinal: 1, code: -1, desc: "未知异常" },
+  "NetworkError": { name: "NetworkError", ordinal: 2, code: 500, desc: "网络错误" },
+  "EncodeError": { name: "EncodeError", ordinal: 3, code: 600, desc: "编码错误" },
+  "Member": { name: "Member", ordinal: 4, code: 2, desc: "支付服务" },
+  "Trade": { name: "Trade", ordinal: 5, code: 2, desc: "交易服务" },
+  "Delivery": { name: "Delivery", ordinal: 6, code: 2, desc: "履约服务" },
+} as const;
+
+// internal.Module
+export type Module = "Issues" | "MergeRequests";
+
+export const Module = {
+  "Issues": { name: "Issues", ordinal: 0, basePath: "/issues/", perms: ["AddLabels", "AddTopic"] },
+  "MergeRequests": { name: "MergeRequests", ordinal: 1, basePath: "/merge/", perms: ["ViewMergeRequest", "ApproveMergeRequest", "DeleteMergeRequest"] },
+} as const;
+
+// internal.Permission
+export type Permission = "AddLabels" | "AddTopic" | "ViewMergeRequest" | "ApproveMergeRequest" | "DeleteMergeRequest";
+
+export const Permission = {
+  "AddLabels": { name: "AddLabels", ordinal: 0 },
+  "AddTopic": { name: "AddTopic", ordinal: 1 },
+  "ViewMergeRequest": { name: "ViewMergeRequest", ordinal: 2 },
+  "ApproveMergeRequest": { name: "ApproveMergeRequest", ordinal: 3 },
+  "DeleteMergeRequest": { name: "DeleteMergeRequest", ordinal: 4 },
+} as const;
+
+// internal.ReverseState
+export type ReverseState = "Created" | "Failed" | "Refunded";
+
+export const ReverseState = {
+  "Created": { name: "Created", ordinal: 0, isFinal: false },
+  "Failed": { name: "Failed", ordinal: 1, isFinal: true },
+  "Refunded": { name: "Refunded", ordinal: 2, isFinal: true },
+} as const;
+
+// internal.Role
+export type Role = "Reporter" | "Developer" | "Owner";
+
+export const Role = {
+  "Reporter": { name: "Reporter", ordinal: 0 },
+  "Developer": { name: "Developer", ordinal: 1 },
+  "Owner": { name: "Owner", ordinal: 2 },
+} as const;
+
+// internal.TradeState
+export type TradeState = "Created" | "Failed" | "Paid" | "Shipped" | "Delivered";
+
+export const TradeState = {
+  "Created": { name: "Created", ordinal: 0, isFinal: false },
+  "Failed": { name: "Failed", ordinal: 1, isFinal: true },
+  "Paid": { name: "Paid", ordinal: 2, isFinal: false },
+  "Shipped": { name: "Shipped", ordinal: 3, isFinal: false },
+  "Delivered": { name: "Delivered", ordinal: 4, isFinal: true },
+} as const;
@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"github.com/lvyahui8/goenum"
+	"time"
+)
+
+// TradeOrder 包含枚举字段的结构体示例
+type TradeOrder struct {
+	ID        int64                             `json:"id"`
+	State     TradeState                        `json:"state"`
+	History   []TradeState                      `json:"history,omitempty"`
+	Code      ErrorCode                         `json:"code"`
+	Color     *ColorEnum                        `json:"color,omitempty"`
+	Perms     *goenum.UnsafeEnumSet[Permission] `json:"perms"`
+	Items     []TradeItem                       `json:"items"`
+	CreatedAt time.Time                         `json:"createdAt"`
+}
+
+type TradeItem struct {
+	Name    string       `json:"name"`
+	Price   float64      `json:"price"`
+	Reverse ReverseState `json:"reverse"`
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"errors"
+	"github.com/lvyahui8/goenum"
+	"github.com/lvyahui8/goenum/goenumtest"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+type ShipRequest struct {
+	State    TradeState   `goenum:"oneof=Paid|Shipped"`
+	Next     string       `goenum:"type=TradeState,!final,required"`
+	Previous []TradeState `goenum:"type=internal.TradeState,final"`
+	Color    *ColorEnum   `goenum:"required"`
+	Items    []ShipItem
+}
+
+type ShipItem struct {
+	Reverse *string `goenum:"type=ReverseState,oneof=Refunded"`
+}
+
+func TestValidate(t *testing.T) {
+	refunded := "Refunded"
+	t.Run("Valid", func(t *testing.T) {
+		req := ShipRequest{State: TradePaid, Next: "Shipped", Previous: []TradeState{TradeDelivered}, Color: Red,
+			Items: []ShipItem{{Reverse: &refunded}, {}}}
+		require.Nil(t, goenum.Validate(req))
+		require.Nil(t, goenum.Validate(&req))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		created := "Created"
+		req := &ShipRequest{State: TradeCreated, Previous: []TradeState{TradeDelivered, TradePaid},
+			Items: []ShipItem{{Reverse: &refunded}, {Reverse: &created}}}
+		err := goenum.Validate(req)
+		var validationErr *goenum.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		var fields []string
+		for _, fe := range validationErr.Errors {
+			fields = append(fields, fe.Field)
+		}
+		require.Equal(t, []string{"ShipRequest.State", "ShipRequest.Next", "ShipRequest.Previous[1]",
+			"ShipRequest.Color", "ShipRequest.Items[1].Reverse"}, fields)
+		require.Equal(t, []string{"Paid", "Shipped"}, validationErr.Errors[0].Allowed)
+		require.Equal(t, []string{"Created", "Paid", "Shipped"}, validationErr.Errors[1].Allowed)
+		require.Equal(t, []string{"Failed", "Delivered"}, validationErr.Errors[2].Allowed)
+		require.Equal(t, "Created", validationErr.Errors[4].Value)
+		require.Contains(t, err.Error(), `ShipRequest.State: invalid value "Created", allowed: Paid, Shipped`)
+	})
+	t.Run("InvalidTag", func(t *testing.T) {
+		cases := []any{
+			struct {
+				S string `goenum:"oneof=Paid"`
+			}{"Paid"},
+			struct {
+				S string `goenum:"type=Unknown"`
+			}{"Paid"},
+			struct {
+				S TradeState `goenum:"type=ReverseState"`
+			}{TradePaid},
+			struct {
+				S TradeState `goenum:"oneof=Unknown"`
+			}{TradePaid},
+			struct {
+				S TradeState `goenum:"unknownPredicate"`
+			}{TradePaid},
+			struct {
+				S int `goenum:"required"`
+			}{1},
+		}
+		for _, c := range cases {
+			require.NotNil(t, goenum.Validate(c))
+		}
+		require.NotNil(t, goenum.Validate("str"))
+	})
+}
+
+// Notice 谓词名以not开头
+type Notice struct {
+	goenum.Enum
+	sent bool
+}
+
+func (n Notice) Nothing() bool {
+	return !n.sent
+}
+
+type NoticeRequest struct {
+	Pending Notice `goenum:"nothing"`
+	Done    Notice `goenum:"!nothing"`
+}
+
+func TestValidate_Predicate(t *testing.T) {
+	s := goenumtest.NewSandbox(t)
+	draft := goenumtest.MustNewEnum[Notice](s, "Draft")
+	sent := goenumtest.MustNewEnum[Notice](s, "Sent", Notice{sent: true})
+	require.Nil(t, goenum.Validate(NoticeRequest{Pending: draft, Done: sent}))
+	err := goenum.Validate(NoticeRequest{Pending: sent, Done: draft})
+	require.EqualError(t, err, `NoticeRequest.Pending: invalid value "Sent", allowed: Draft; `+
+		`NoticeRequest.Done: invalid value "Draft", allowed: Sent`)
+	// 注册表变化后重新计算允许的成员
+	goenumtest.MustNewEnum[Notice](s, "Queued")
+	require.Nil(t, goenum.Validate(NoticeRequest{Pending: goenum.MustValueOf[Notice]("Queued"), Done: sent}))
+}
+
+// fakeFieldLevel Mimic validator.FieldLevel of github.com/go-playground/validator
+type fakeFieldLevel interface {
+	Field() reflect.Value
+	Parent() reflect.Value
+	StructFieldName() string
+	Param() string
+}
+
+// fakeFunc Mimic validator.Func
+type fakeFunc func(fl fakeFieldLevel) bool
+
+type fakeValidator struct {
+	validations map[string]fakeFunc
+}
+
+func (v *fakeValidator) RegisterValidation(tag string, fn fakeFunc, callValidationEvenIfNull ...bool) error {
+	v.validations[tag] = fn
+	return nil
+}
+
+type fieldLevel struct {
+	parent reflect.Value
+	name   string
+}
+
+func (fl fieldLevel) Field() reflect.Value {
+	return fl.parent.FieldByName(fl.name)
+}
+
+func (fl fieldLevel) Parent() reflect.Value {
+	return fl.parent
+}
+
+func (fl fieldLevel) StructFieldName() string {
+	return fl.name
+}
+
+func (fl fieldLevel) Param() string {
+	return ""
+}
+
+func TestRegisterValidation(t *testing.T) {
+	v := &fakeValidator{validations: make(map[string]fakeFunc)}
+	require.Nil(t, goenum.RegisterValidation(v.RegisterValidation))
+	fn := v.validations["goenum"]
+	require.NotNil(t, fn)
+	require.True(t, fn(fieldLevel{parent: reflect.ValueOf(ShipRequest{State: TradePaid}), name: "State"}))
+	require.False(t, fn(fieldLevel{parent: reflect.ValueOf(ShipRequest{State: TradeCreated}), name: "State"}))
+	require.False(t, fn(fieldLevel{parent: reflect.ValueOf(ShipRequest{Next: "Delivered"}), name: "Next"}))
+}
@@ -0,0 +1,72 @@
+//go:build go1.23
+
+package goenum
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// All Iterate all enumeration instances of T in ordinal order
+func All[T EnumDefinition]() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, e := range ValuesView[T]() {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Filter Iterate the enumeration instances of seq satisfying pred
+func Filter[T EnumDefinition](seq iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := range seq {
+			if pred(e) && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// Map Iterate the results of f applied to the enumeration instances of seq, such as their names or codes
+func Map[T EnumDefinition, R any](seq iter.Seq[T], f func(T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for e := range seq {
+			if !yield(f(e)) {
+				return
+			}
+		}
+	}
+}
+
+// All Iterate the elements of the set in ordinal order
+func (set *UnsafeEnumSet[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		set.Each(yield)
+	}
+}
+
+// Backward Iterate the elements of the set in reverse ordinal order
+func (set *UnsafeEnumSet[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		values := ValuesViewIn[E](set.registry())
+		for i := len(set.elements) - 1; i >= 0; i-- {
+			for word := set.elements[i]; word != 0; {
+				high := 63 - bits.LeadingZeros64(word)
+				ordinal := i<<6 + high
+				if ordinal < len(values) && !yield(values[ordinal]) {
+					return
+				}
+				word &^= uint64(1) << high
+			}
+		}
+	}
+}
+
+// All Iterate the entries of the map in ordinal order of the keys
+func (m *EnumMap[E, V]) All() iter.Seq2[E, V] {
+	return func(yield func(E, V) bool) {
+		m.Each(yield)
+	}
+}
@@ -0,0 +1,258 @@
+package goenum
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Lock Snapshot of the registered enumeration schema, checked in as a lockfile to detect breaking changes
+type Lock struct {
+	Types []LockType `json:"types"`
+}
+
+// LockType The members of an enumeration type in the Lock
+type LockType struct {
+	Name    string       `json:"name"`
+	PkgPath string       `json:"pkgPath"`
+	Members []LockMember `json:"members"`
+}
+
+// LockMember A member in the Lock, attributes such as stable codes are recorded to detect value changes
+type LockMember struct {
+	Name       string      `json:"name"`
+	Ordinal    int         `json:"ordinal"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+}
+
+// LockChangeKind Kind of LockChange
+type LockChangeKind string
+
+const (
+	LockTypeAdded        LockChangeKind = "type_added"
+	LockTypeRemoved      LockChangeKind = "type_removed"
+	LockMemberAdded      LockChangeKind = "member_added"
+	LockMemberRemoved    LockChangeKind = "member_removed"
+	LockMemberRenamed    LockChangeKind = "member_renamed"
+	LockOrdinalChanged   LockChangeKind = "ordinal_changed"
+	LockAttributeChanged LockChangeKind = "attribute_changed"
+)
+
+// LockChange A difference between two Locks
+type LockChange struct {
+	Kind LockChangeKind `json:"kind"`
+	Type string         `json:"type"`
+	// Member The member name in the old Lock, or in the new Lock if added
+	Member string `json:"member,omitempty"`
+	// Attribute The attribute name of LockAttributeChanged
+	Attribute string `json:"attribute,omitempty"`
+	// Old, New The changed values: ordinals, names or attribute values
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+	// Breaking Whether the change may break the existing data or clients, only additions are not breaking
+	Breaking bool `json:"breaking"`
+}
+
+func (c LockChange) String() string {
+	var sb strings.Builder
+	if c.Breaking {
+		sb.WriteString("BREAKING ")
+	}
+	sb.WriteString(c.Type)
+	if c.Member != "" {
+		sb.WriteString("." + c.Member)
+	}
+	switch c.Kind {
+	case LockTypeAdded:
+		sb.WriteString(": type added")
+	case LockTypeRemoved:
+		sb.WriteString(": type removed")
+	case LockMemberAdded:
+		fmt.Fprintf(&sb, ": member added with ordinal %v", c.New)
+	case LockMemberRemoved:
+		fmt.Fprintf(&sb, ": member removed, its ordinal was %v", c.Old)
+	case LockMemberRenamed:
+		fmt.Fprintf(&sb, ": probably renamed to %v, the ordinal is unchanged", c.New)
+	case LockOrdinalChanged:
+		fmt.Fprintf(&sb, ": ordinal changed from %v to %v", c.Old, c.New)
+	case LockAttributeChanged:
+		fmt.Fprintf(&sb, ": attribute %s changed from %s to %s", c.Attribute, jsonString(c.Old), jsonString(c.New))
+	}
+	return sb.String()
+}
+
+// LockReport The result of CompareLock
+type LockReport struct {
+	Changes []LockChange `json:"changes"`
+}
+
+// Breaking Whether any change is breaking
+func (r *LockReport) Breaking() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// String The human-readable report, one change per line
+func (r *LockReport) String() string {
+	if len(r.Changes) == 0 {
+		return "no changes\n"
+	}
+	var sb strings.Builder
+	for _, c := range r.Changes {
+		sb.WriteString(c.String() + "\n")
+	}
+	return sb.String()
+}
+
+// Snapshot Create the Lock of the enumeration types declared in the packages, all types if no package is specified
+func Snapshot(pkgPaths ...string) *Lock {
+	lock := &Lock{Types: []LockType{}}
+	for _, d := range Types() {
+		if len(pkgPaths) > 0 && !containsString(pkgPaths, d.PkgPath) {
+			continue
+		}
+		lt := LockType{Name: d.Name, PkgPath: d.PkgPath}
+		for _, m := range d.Members {
+			lt.Members = append(lt.Members, LockMember{Name: m.Name, Ordinal: m.Ordinal, Attributes: m.Attributes})
+		}
+		lock.Types = append(lock.Types, lt)
+	}
+	return lock
+}
+
+// CompareLock Compare the new Lock with the old one. Breaking changes are:
+//   - removed types and members
+//   - changed ordinals, which are declaration-order based, so inserting or reordering members shifts them
+//   - changed attribute values, such as the stable codes stored or sent to other services
+//   - renamed members, reported when a removed member and an added member have the same ordinal
+func CompareLock(old, new *Lock) *LockReport {
+	report := &LockReport{Changes: []LockChange{}}
+	names := lockTypeNames(old, new)
+	newTypes := make(map[string]LockType, len(new.Types))
+	for _, t := range new.Types {
+		newTypes[t.key()] = t
+	}
+	oldTypes := make(map[string]bool, len(old.Types))
+	for _, ot := range old.Types {
+		oldTypes[ot.key()] = true
+		nt, ok := newTypes[ot.key()]
+		if !ok {
+			report.Changes = append(report.Changes, LockChange{Kind: LockTypeRemoved, Type: names[ot.key()], Breaking: true})
+			continue
+		}
+		report.Changes = append(report.Changes, compareMembers(names[ot.key()], ot, nt)...)
+	}
+	for _, nt := range new.Types {
+		if !oldTypes[nt.key()] {
+			report.Changes = append(report.Changes, LockChange{Kind: LockTypeAdded, Type: names[nt.key()]})
+		}
+	}
+	return report
+}
+
+// key Types are identified by the package path and the name, the name alone is only qualified by the package name
+func (t LockType) key() string {
+	return t.PkgPath + "." + t.Name
+}
+
+// lockTypeNames The type names reported in the changes, names shared by types of different packages are
+// qualified by the full package path, such as example.com/a/status.Status
+func lockTypeNames(locks ...*Lock) map[string]string {
+	keys := make(map[string]map[string]bool)
+	for _, l := range locks {
+		for _, t := range l.Types {
+			if keys[t.Name] == nil {
+				keys[t.Name] = make(map[string]bool)
+			}
+			keys[t.Name][t.key()] = true
+		}
+	}
+	names := make(map[string]string)
+	for _, l := range locks {
+		for _, t := range l.Types {
+			names[t.key()] = t.Name
+			if len(keys[t.Name]) > 1 && t.PkgPath != "" {
+				names[t.key()] = t.PkgPath + "." + t.Name[strings.LastIndex(t.Name, ".")+1:]
+			}
+		}
+	}
+	return names
+}
+
+func compareMembers(typeName string, ot, nt LockType) (changes []LockChange) {
+	newMembers := make(map[string]LockMember, len(nt.Members))
+	newOrdinals := make(map[int]LockMember, len(nt.Members))
+	for _, m := range nt.Members {
+		newMembers[m.Name] = m
+		newOrdinals[m.Ordinal] = m
+	}
+	oldMembers := make(map[string]bool, len(ot.Members))
+	for _, m := range ot.Members {
+		oldMembers[m.Name] = true
+	}
+	renamed := make(map[string]bool)
+	for _, om := range ot.Members {
+		nm, ok := newMembers[om.Name]
+		if !ok {
+			if candidate, exist := newOrdinals[om.Ordinal]; exist && !oldMembers[candidate.Name] {
+				renamed[candidate.Name] = true
+				changes = append(changes, LockChange{Kind: LockMemberRenamed, Type: typeName, Member: om.Name,
+					Old: om.Name, New: candidate.Name, Breaking: true})
+			} else {
+				changes = append(changes, LockChange{Kind: LockMemberRemoved, Type: typeName, Member: om.Name,
+					Old: om.Ordinal, Breaking: true})
+			}
+			continue
+		}
+		if om.Ordinal != nm.Ordinal {
+			changes = append(changes, LockChange{Kind: LockOrdinalChanged, Type: typeName, Member: om.Name,
+				Old: om.Ordinal, New: nm.Ordinal, Breaking: true})
+		}
+		changes = append(changes, compareAttributes(typeName, om, nm)...)
+	}
+	for _, nm := range nt.Members {
+		if !oldMembers[nm.Name] && !renamed[nm.Name] {
+			changes = append(changes, LockChange{Kind: LockMemberAdded, Type: typeName, Member: nm.Name, New: nm.Ordinal})
+		}
+	}
+	return
+}
+
+// compareAttributes Attribute values are compared by their JSON encodings, since the numbers read from the lockfile are float64
+func compareAttributes(typeName string, om, nm LockMember) (changes []LockChange) {
+	newValues := make(map[string]any, len(nm.Attributes))
+	for _, a := range nm.Attributes {
+		newValues[a.Name] = a.Value
+	}
+	oldNames := make(map[string]bool, len(om.Attributes))
+	for _, a := range om.Attributes {
+		oldNames[a.Name] = true
+		v, ok := newValues[a.Name]
+		if !ok || jsonString(a.Value) != jsonString(v) {
+			changes = append(changes, LockChange{Kind: LockAttributeChanged, Type: typeName, Member: om.Name,
+				Attribute: a.Name, Old: a.Value, New: v, Breaking: true})
+		}
+	}
+	for _, a := range nm.Attributes {
+		if !oldNames[a.Name] {
+			changes = append(changes, LockChange{Kind: LockAttributeChanged, Type: typeName, Member: om.Name,
+				Attribute: a.Name, New: a.Value})
+		}
+	}
+	return
+}
+
+func jsonString(v any) string {
+	if v == nil {
+		return "none"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
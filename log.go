@@ -0,0 +1,181 @@
+//go:build go1.21
+
+package goenum
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// Redacted The value logged in place of sensitive enumerations and attributes by LogHandler
+const Redacted = "[REDACTED]"
+
+// LogValue Implement slog.LogValuer, log as a group of name, ordinal and type, or Redacted if the type is marked
+// by MarkSensitive. Wrap the handler by NewLogHandler to log compact names or attributes
+func (e Enum) LogValue() slog.Value {
+	if sensitive, ok := sensitiveTypes.Load(e._type); ok && sensitive.(*sensitiveSpec).all {
+		return slog.StringValue(Redacted)
+	}
+	return slog.GroupValue(
+		slog.String("name", e.name),
+		slog.Int("ordinal", e.index),
+		slog.String("type", e._type),
+	)
+}
+
+// LogValue Implement slog.LogValuer, log as a group of type and names, or Redacted like Enum.LogValue
+func (set *UnsafeEnumSet[E]) LogValue() slog.Value {
+	return set.logValue(&LogHandlerOptions{})
+}
+
+func (set *UnsafeEnumSet[E]) logValue(opts *LogHandlerOptions) slog.Value {
+	typeName := typeKey(reflect.TypeOf((*E)(nil)).Elem())
+	if sensitive, ok := sensitiveTypes.Load(typeName); ok && sensitive.(*sensitiveSpec).all {
+		return slog.StringValue(Redacted)
+	}
+	if opts.Compact {
+		return slog.StringValue(set.String())
+	}
+	names := set.Names()
+	if names == nil {
+		names = []string{}
+	}
+	return slog.GroupValue(slog.String("type", typeName), slog.Any("names", names))
+}
+
+// enumSetLogger Log an EnumSet without knowing its generic type
+type enumSetLogger interface {
+	logValue(opts *LogHandlerOptions) slog.Value
+}
+
+// sensitiveSpec all 整个枚举类型敏感，否则只有attributes中的属性敏感
+type sensitiveSpec struct {
+	all        bool
+	attributes map[string]bool
+}
+
+// sensitiveTypes type key -> *sensitiveSpec
+var sensitiveTypes sync.Map
+
+// MarkSensitive Mark the enumeration type T sensitive for LogHandler. Without attribute names, the instances
+// and EnumSets of T are logged as Redacted, otherwise only the listed attributes are redacted
+func MarkSensitive[T EnumDefinition](attributes ...string) {
+	spec := &sensitiveSpec{all: len(attributes) == 0, attributes: make(map[string]bool)}
+	for _, attr := range attributes {
+		spec.attributes[attr] = true
+	}
+	sensitiveTypes.Store(typeKey(reflect.TypeOf((*T)(nil)).Elem()), spec)
+}
+
+// UnmarkSensitive Undo MarkSensitive of T, such as in t.Cleanup of a test marking T
+func UnmarkSensitive[T EnumDefinition]() {
+	sensitiveTypes.Delete(typeKey(reflect.TypeOf((*T)(nil)).Elem()))
+}
+
+// LogHandlerOptions Options of NewLogHandler
+type LogHandlerOptions struct {
+	// Compact Log enumerations as their names, and EnumSets as [A,B], instead of groups
+	Compact bool
+	// Attributes Add the attributes of enumerations to their groups, see Attributes. Ignored if Compact
+	Attributes bool
+}
+
+// LogHandler A slog.Handler middleware formatting the enumerations and EnumSets in the records and attributes
+// according to the options, and redacting the sensitive ones marked by MarkSensitive
+type LogHandler struct {
+	next slog.Handler
+	opts LogHandlerOptions
+}
+
+// NewLogHandler Wrap next, opts can be nil
+func NewLogHandler(next slog.Handler, opts *LogHandlerOptions) *LogHandler {
+	h := &LogHandler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	res := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		res.AddAttrs(h.rewrite(a))
+		return true
+	})
+	return h.next.Handle(ctx, res)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rewritten := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		rewritten[i] = h.rewrite(a)
+	}
+	return &LogHandler{next: h.next.WithAttrs(rewritten), opts: h.opts}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// rewrite Replace the enumerations in a, including those nested in groups
+func (h *LogHandler) rewrite(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = h.rewrite(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindLogValuer:
+		switch v := a.Value.Any().(type) {
+		case EnumDefinition:
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				return a
+			}
+			return slog.Attr{Key: a.Key, Value: h.enumValue(v)}
+		case enumSetLogger:
+			return slog.Attr{Key: a.Key, Value: v.logValue(&h.opts)}
+		}
+		return h.rewrite(slog.Attr{Key: a.Key, Value: a.Value.Resolve()})
+	}
+	return a
+}
+
+func (h *LogHandler) enumValue(e EnumDefinition) slog.Value {
+	var spec *sensitiveSpec
+	if s, ok := sensitiveTypes.Load(e.Type()); ok {
+		spec = s.(*sensitiveSpec)
+	}
+	if spec != nil && spec.all {
+		return slog.StringValue(Redacted)
+	}
+	if h.opts.Compact {
+		return slog.StringValue(e.Name())
+	}
+	attrs := []slog.Attr{
+		slog.String("name", e.Name()),
+		slog.Int("ordinal", e.Ordinal()),
+		slog.String("type", e.Type()),
+	}
+	if h.opts.Attributes {
+		var values []slog.Attr
+		for _, attr := range Attributes(e) {
+			if spec != nil && spec.attributes[attr.Name] {
+				values = append(values, slog.String(attr.Name, Redacted))
+				continue
+			}
+			values = append(values, slog.Any(attr.Name, attr.Value))
+		}
+		if len(values) > 0 {
+			attrs = append(attrs, slog.Attr{Key: "attributes", Value: slog.GroupValue(values...)})
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
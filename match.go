@@ -0,0 +1,182 @@
+package goenum
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NonExhaustiveError Some members of the type have no handler in a Matcher
+type NonExhaustiveError struct {
+	Type string
+	// Missing Names of the members without handler, sorted by ordinal
+	Missing []string
+}
+
+func (e *NonExhaustiveError) Error() string {
+	return "goenum: Match of " + e.Type + " misses " + strings.Join(e.Missing, ", ")
+}
+
+// Matcher Map each member of T to a handler, the runtime counterpart of an exhaustive switch.
+// Handlers are stored in a table indexed by ordinal, so Apply dispatches in O(1):
+//
+//	var stateLabel = goenum.NewMatcher[TradeState, string]().
+//		Case(TradeCreated, func(TradeState) string { return "Awaiting payment" }).
+//		Case(TradePaid, func(TradeState) string { return "Paid" }).
+//		Exhaustive()
+//
+//	label := stateLabel.Apply(state)
+//
+// A Matcher must not be modified after the first Apply, it is safe for concurrent Apply
+type Matcher[T EnumDefinition, R any] struct {
+	// reg 成员所属的注册表，nil表示默认注册表
+	reg *Registry
+	// handlers 按序号索引的分派表，names用于确认序号对应的是同一个成员
+	handlers       []func(T) R
+	names          []string
+	defaultHandler func(T) R
+	exhaustive     bool
+	checkOnce      sync.Once
+	checkErr       error
+}
+
+// NewMatcher Create an empty Matcher of the members of T in the default registry
+func NewMatcher[T EnumDefinition, R any]() *Matcher[T, R] {
+	return &Matcher[T, R]{}
+}
+
+// NewMatcherIn Create an empty Matcher of the members of T in the registry reg and its ancestors
+func NewMatcherIn[T EnumDefinition, R any](reg *Registry) *Matcher[T, R] {
+	return &Matcher[T, R]{reg: reg}
+}
+
+func (m *Matcher[T, R]) registry() *Registry {
+	if m.reg == nil {
+		return defaultRegistry
+	}
+	return m.reg
+}
+
+// Case Handle e by f. It panics if e already has a handler or is not a registered member of T in the registry of m
+func (m *Matcher[T, R]) Case(e T, f func(e T) R) *Matcher[T, R] {
+	ordinal := cacheOf[T](m.registry()).mustOrdinalOf(e)
+	if ordinal < len(m.handlers) && m.handlers[ordinal] != nil {
+		panic("goenum: duplicate case " + strconv.Quote(e.Name()) + " of " + e.Type())
+	}
+	if ordinal >= len(m.handlers) {
+		size := SizeIn[T](m.registry())
+		if size <= ordinal {
+			size = ordinal + 1
+		}
+		handlers, names := make([]func(T) R, size), make([]string, size)
+		copy(handlers, m.handlers)
+		copy(names, m.names)
+		m.handlers, m.names = handlers, names
+	}
+	m.handlers[ordinal], m.names[ordinal] = f, e.Name()
+	return m
+}
+
+// Default Handle the members without a case by f, such as the dynamic members. Check and Exhaustive ignore it
+func (m *Matcher[T, R]) Default(f func(e T) R) *Matcher[T, R] {
+	m.defaultHandler = f
+	return m
+}
+
+// Check Return a *NonExhaustiveError if any member of T in the registry of m has no case
+func (m *Matcher[T, R]) Check() error {
+	var missing []string
+	var typeName string
+	for _, e := range ValuesViewIn[T](m.registry()) {
+		typeName = e.Type()
+		if _, ok := m.handler(e); !ok {
+			missing = append(missing, e.Name())
+		}
+	}
+	if len(missing) > 0 {
+		return &NonExhaustiveError{Type: typeName, Missing: missing}
+	}
+	return nil
+}
+
+// Exhaustive Require a case for every member, checked by the first Apply, which panics with a *NonExhaustiveError.
+// Checking at the first use rather than at once covers the members declared after the Matcher in the same package
+func (m *Matcher[T, R]) Exhaustive() *Matcher[T, R] {
+	m.exhaustive = true
+	return m
+}
+
+// MustBeExhaustive Like Exhaustive, but check at once and panic at the initialization of the package declaring the Matcher.
+// The members of T must be registered before, such as declared in an imported package
+func (m *Matcher[T, R]) MustBeExhaustive() *Matcher[T, R] {
+	if err := m.Check(); err != nil {
+		panic(err)
+	}
+	return m.Exhaustive()
+}
+
+// Apply Call the handler of e, or the default handler if e has no case.
+// It panics with a *NonExhaustiveError if there is no handler at all
+func (m *Matcher[T, R]) Apply(e T) R {
+	if m.exhaustive {
+		m.checkOnce.Do(func() {
+			m.checkErr = m.Check()
+		})
+		if m.checkErr != nil {
+			panic(m.checkErr)
+		}
+	}
+	if f, ok := m.handler(e); ok {
+		return f(e)
+	}
+	if m.defaultHandler != nil {
+		return m.defaultHandler(e)
+	}
+	panic(&NonExhaustiveError{Type: e.Type(), Missing: []string{e.Name()}})
+}
+
+func (m *Matcher[T, R]) handler(e T) (func(T) R, bool) {
+	ordinal := e.Ordinal()
+	if ordinal < 0 || ordinal >= len(m.handlers) || m.handlers[ordinal] == nil || m.names[ordinal] != e.Name() {
+		return nil, false
+	}
+	return m.handlers[ordinal], true
+}
+
+// Matching An inline match of a single value created by Match, see Matcher for the reusable version
+type Matching[T EnumDefinition, R any] struct {
+	e       T
+	matcher Matcher[T, R]
+}
+
+// Match Start an inline match of e:
+//
+//	label := goenum.Match[TradeState, string](state).
+//		Case(TradeCreated, func(TradeState) string { return "Awaiting payment" }).
+//		Case(TradePaid, func(TradeState) string { return "Paid" }).
+//		Exhaustive()
+//
+// The cases are checked on every evaluation, prefer a Matcher declared once in hot paths
+func Match[T EnumDefinition, R any](e T) *Matching[T, R] {
+	return &Matching[T, R]{e: e}
+}
+
+// Case Handle the member by f, it panics like Matcher.Case
+func (m *Matching[T, R]) Case(e T, f func(e T) R) *Matching[T, R] {
+	m.matcher.Case(e, f)
+	return m
+}
+
+// Exhaustive Evaluate the handler of the matched value, panic with a *NonExhaustiveError if any member has no case
+func (m *Matching[T, R]) Exhaustive() R {
+	return m.matcher.Exhaustive().Apply(m.e)
+}
+
+// Result Evaluate the handler of the matched value, return false if it has no case
+func (m *Matching[T, R]) Result() (r R, ok bool) {
+	f, ok := m.matcher.handler(m.e)
+	if !ok {
+		return
+	}
+	return f(m.e), true
+}
@@ -0,0 +1,17 @@
+package goenum
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TestMatcher_CaseSize 处理函数按Matcher所属注册表的成员数分配，而不是默认注册表的
+func TestMatcher_CaseSize(t *testing.T) {
+	reg := NewRegistry(nil)
+	first := NewEnumIn[Statement](reg, "First")
+	NewEnumIn[Statement](reg, "Second")
+	m := NewMatcherIn[Statement, int](reg).Case(first, func(Statement) int { return 1 })
+	require.Len(t, m.handlers, 2)
+	require.Len(t, NewMatcher[Statement, int]().Case(Decl, func(Statement) int { return 0 }).handlers, Size[Statement]())
+	require.Equal(t, 1, m.Default(func(Statement) int { return 0 }).Apply(first))
+}
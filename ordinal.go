@@ -0,0 +1,70 @@
+package goenum
+
+// First The enumeration instance with the smallest ordinal, return false if the type has no members
+func First[T EnumDefinition]() (t T, valid bool) {
+	values := ValuesView[T]()
+	if len(values) == 0 {
+		return
+	}
+	return values[0], true
+}
+
+// Last The enumeration instance with the largest ordinal, return false if the type has no members
+func Last[T EnumDefinition]() (t T, valid bool) {
+	values := ValuesView[T]()
+	if len(values) == 0 {
+		return
+	}
+	return values[len(values)-1], true
+}
+
+// Next The enumeration instance following e in ordinal order, return false if e is the last one.
+// Useful to walk progressive states, such as TradeCreated -> TradePaid -> TradeShipped
+func Next[T EnumDefinition](e T) (T, bool) {
+	return ValueOfOrdinal[T](e.Ordinal() + 1)
+}
+
+// Prev The enumeration instance preceding e in ordinal order, return false if e is the first one
+func Prev[T EnumDefinition](e T) (T, bool) {
+	return ValueOfOrdinal[T](e.Ordinal() - 1)
+}
+
+// NextWrap Like Next, but wrap around to the first instance after the last one
+func NextWrap[T EnumDefinition](e T) T {
+	if t, valid := Next(e); valid {
+		return t
+	}
+	t, _ := First[T]()
+	return t
+}
+
+// PrevWrap Like Prev, but wrap around to the last instance before the first one
+func PrevWrap[T EnumDefinition](e T) T {
+	if t, valid := Prev(e); valid {
+		return t
+	}
+	t, _ := Last[T]()
+	return t
+}
+
+// ValuesBetween The enumeration instances with ordinals from from.Ordinal() to to.Ordinal(), both inclusive, like AddRange of EnumSet.
+// Return nil if from is after to
+func ValuesBetween[T EnumDefinition](from, to T) []T {
+	values := ValuesView[T]()
+	begin, end := from.Ordinal(), to.Ordinal()
+	if begin < 0 {
+		begin = 0
+	}
+	if end >= len(values) {
+		end = len(values) - 1
+	}
+	if begin > end {
+		return nil
+	}
+	return append([]T(nil), values[begin:end+1]...)
+}
+
+// Between Whether the ordinal of e is between the ordinals of lo and hi, both inclusive
+func Between[T EnumDefinition](e, lo, hi T) bool {
+	return lo.Ordinal() <= e.Ordinal() && e.Ordinal() <= hi.Ordinal()
+}
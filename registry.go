@@ -0,0 +1,320 @@
+package goenum
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// registry Immutable view of the enumerations registered to a Registry once published, readers always see a consistent
+// snapshot without locking. Reloading modifies a clone and publishes it atomically (copy on write), while NewEnum
+// appends to an unpublished draft in place, see Registry.draft.
+type registry struct {
+	// parent The parent of the Registry
+	parent *Registry
+	// name2enums Name to enumeration instance mapping.
+	// Value uses slice to store enumeration instances with different types but conflicting names
+	name2enums map[string][]EnumDefinition
+	// type2enums The mapping from enumeration type to all enumerations,
+	// where key is the string representation of the enumeration type
+	type2enums map[string][]EnumDefinition
+	// typeIndex Store instance counters of different enumeration types for calculating Ordinal.
+	typeIndex map[string]int
+	// deprecated Names of the members removed by reloading, keyed by type
+	deprecated map[string]map[string]bool
+}
+
+// Registry A set of enumeration types and their members. Registries are isolated from each other, so tests and tenants
+// can register their own members without affecting the others. A registry created with a parent falls back to it:
+// lookups search the registry first and then its ancestors, and the members registered to the registry take the
+// ordinals following the members of the same type in the ancestors. The ancestors must therefore register their
+// members before the children, like the members declared by NewEnum are registered before the dynamic ones: once a
+// child registers a member of a type, registering the type to the ancestors panics (NewEnumIn) or fails (DynamicType),
+// instead of giving the new members the ordinals taken by the child.
+//
+// The package level functions, such as NewEnum and ValueOf, use the default registry.
+type Registry struct {
+	parent *Registry
+	// current *registry
+	current atomic.Value
+	// mu Serialize the modification of the registry, and protect draft
+	mu sync.Mutex
+	// draft The snapshot NewEnum appends to in place, published by the next read, nil if none.
+	// 连续的静态注册只复制一次快照，并在下一次读取时一次性发布
+	draft *registry
+	// dirty 1 if the draft has members not published yet, read without locking
+	dirty int32
+	// extended The types extended by the descendants, protected by mu
+	extended map[string]bool
+	// watchMu Protect watchers and deliveries
+	watchMu sync.Mutex
+	// watchers Subscriptions keyed by type
+	watchers map[string][]*subscription
+	// deliveries The order of the change events keyed by type, delivered is signaled after each event
+	deliveries map[string]*delivery
+	delivered  *sync.Cond
+	// caches *typeCache[T] keyed by cacheKey[T]
+	caches sync.Map
+}
+
+var defaultRegistry = NewRegistry(nil)
+
+// NewRegistry Create an empty registry, parent is optional
+func NewRegistry(parent *Registry) *Registry {
+	reg := &Registry{parent: parent, extended: make(map[string]bool),
+		watchers: make(map[string][]*subscription), deliveries: make(map[string]*delivery)}
+	reg.delivered = sync.NewCond(&reg.watchMu)
+	reg.current.Store(&registry{
+		parent:     parent,
+		name2enums: make(map[string][]EnumDefinition),
+		type2enums: make(map[string][]EnumDefinition),
+		typeIndex:  make(map[string]int),
+		deprecated: make(map[string]map[string]bool),
+	})
+	return reg
+}
+
+// DefaultRegistry The registry used by the package level functions
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Parent The parent registry, nil for a root registry
+func (reg *Registry) Parent() *Registry {
+	return reg.parent
+}
+
+// Checkpoint The members of a registry recorded by Registry.Checkpoint, intended for tests, see goenumtest.Sandbox
+type Checkpoint struct {
+	reg   *Registry
+	saved *registry
+	// expected 持有者最后一次修改后的快照，恢复时注册表应仍为该快照
+	expected *registry
+}
+
+// Checkpoint Record the members of the registry, Restore drops the members registered afterwards
+func (reg *Registry) Checkpoint() *Checkpoint {
+	saved := reg.load()
+	return &Checkpoint{reg: reg, saved: saved, expected: saved}
+}
+
+// Accept Take the current members as registered by the holder of the checkpoint, call it after each registration
+func (cp *Checkpoint) Accept() {
+	cp.expected = cp.reg.load()
+}
+
+// Restore Restore the recorded members. It fails and keeps the members if the registry has been changed since the last
+// Accept by others, such as a dynamic load or a test running in parallel, whose changes would be dropped silently.
+// Instances created after the checkpoint must not be used after restoring
+func (cp *Checkpoint) Restore() error {
+	reg := cp.reg
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if atomic.LoadInt32(&reg.dirty) != 0 || reg.current.Load().(*registry) != cp.expected {
+		return errors.New("goenum: the registry has been changed by others since the checkpoint, it is not restored")
+	}
+	reg.publish(cp.saved)
+	return nil
+}
+
+// load The published snapshot, the draft is published first if it has new members
+func (reg *Registry) load() *registry {
+	if atomic.LoadInt32(&reg.dirty) != 0 {
+		reg.mu.Lock()
+		reg.publish(reg.draft)
+		reg.mu.Unlock()
+	}
+	return reg.current.Load().(*registry)
+}
+
+// publish Publish r and discard the draft, reg.mu must be held
+func (reg *Registry) publish(r *registry) {
+	if r != nil {
+		reg.current.Store(r)
+	}
+	reg.draft = nil
+	atomic.StoreInt32(&reg.dirty, 0)
+}
+
+// latest The draft if any, otherwise the published snapshot, reg.mu must be held
+func (reg *Registry) latest() *registry {
+	if reg.draft != nil {
+		return reg.draft
+	}
+	return reg.current.Load().(*registry)
+}
+
+// update Apply f to a clone of the latest snapshot and publish the clone.
+// If f returns an error, nothing is published. f must copy the slices before modifying their elements
+func (reg *Registry) update(f func(r *registry) error) error {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	r := reg.latest().clone()
+	if err := f(r); err != nil {
+		return err
+	}
+	reg.publish(r)
+	return nil
+}
+
+// append Apply f to the draft in place, the draft is cloned from the published snapshot if there is none.
+// Readers do not see the draft until the next load publishes it, so a sequence of registrations copies the snapshot
+// once instead of once per member. f may only append members, and must not fail after modifying the draft
+func (reg *Registry) append(f func(r *registry)) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if reg.draft == nil {
+		reg.draft = reg.current.Load().(*registry).clone()
+	}
+	f(reg.draft)
+	atomic.StoreInt32(&reg.dirty, 1)
+}
+
+// values All members of the type registered to the registry and its ancestors, sorted by ordinal
+func (reg *Registry) values(tKey string) []EnumDefinition {
+	own := reg.load().type2enums[tKey]
+	if reg.parent == nil {
+		return own
+	}
+	inherited := reg.parent.values(tKey)
+	if len(own) == 0 {
+		return inherited
+	}
+	return append(inherited[:len(inherited):len(inherited)], own...)
+}
+
+func loadRegistry() *registry {
+	return defaultRegistry.load()
+}
+
+func updateRegistry(f func(r *registry) error) error {
+	return defaultRegistry.update(f)
+}
+
+// clone Shallow copy. The slices are clipped, so appending to them never writes to the arrays shared with r,
+// even if r is published again by a Checkpoint restore
+func (r *registry) clone() *registry {
+	res := &registry{
+		parent:     r.parent,
+		name2enums: make(map[string][]EnumDefinition, len(r.name2enums)+1),
+		type2enums: make(map[string][]EnumDefinition, len(r.type2enums)+1),
+		typeIndex:  make(map[string]int, len(r.typeIndex)+1),
+		deprecated: make(map[string]map[string]bool, len(r.deprecated)),
+	}
+	for k, v := range r.name2enums {
+		res.name2enums[k] = v[:len(v):len(v)]
+	}
+	for k, v := range r.type2enums {
+		res.type2enums[k] = v[:len(v):len(v)]
+	}
+	for k, v := range r.typeIndex {
+		res.typeIndex[k] = v
+	}
+	for k, v := range r.deprecated {
+		res.deprecated[k] = v
+	}
+	return res
+}
+
+// contains Whether the type has a member named name, the ancestors are searched too
+func (r *registry) contains(tKey string, name string) bool {
+	if r.find(tKey, name) != nil {
+		return true
+	}
+	for p := r.parent; p != nil; p = p.parent {
+		if p.load().find(tKey, name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOrdinal The ordinal of the next member of the type registered to r
+func (r *registry) nextOrdinal(tKey string) int {
+	if idx, ok := r.typeIndex[tKey]; ok || r.parent == nil {
+		return idx
+	}
+	return len(r.parent.values(tKey))
+}
+
+// extend Mark the type extended in the ancestors before the first member of the type is registered to r, so the
+// ancestors can not take the ordinals following their members anymore. Return the next ordinal like nextOrdinal
+func (r *registry) extend(tKey string) int {
+	if _, ok := r.typeIndex[tKey]; !ok {
+		// 先标记再读取父注册表的成员，父注册表并发注册的成员要么计入序号，要么注册失败
+		for p := r.parent; p != nil; p = p.parent {
+			p.mu.Lock()
+			p.extended[tKey] = true
+			p.mu.Unlock()
+		}
+	}
+	return r.nextOrdinal(tKey)
+}
+
+// checkExtended Return an error if the type has been extended by the descendants of reg, reg.mu must be held
+func (reg *Registry) checkExtended(tKey string) error {
+	if reg.extended[tKey] {
+		return errors.New("goenum: " + tKey + " is extended by a child registry, its members must be registered before the children's")
+	}
+	return nil
+}
+
+// find Find the member of the type registered to r by name, return nil if not found
+func (r *registry) find(tKey string, name string) EnumDefinition {
+	for _, e := range r.name2enums[name] {
+		if e.Type() == tKey {
+			return e
+		}
+	}
+	return nil
+}
+
+// replace Replace the registered member having the same type and name with e
+func (r *registry) replace(e EnumDefinition) {
+	enums := append([]EnumDefinition(nil), r.type2enums[e.Type()]...)
+	for i, n := range enums {
+		if n.Name() == e.Name() {
+			enums[i] = e
+		}
+	}
+	r.type2enums[e.Type()] = enums
+	named := append([]EnumDefinition(nil), r.name2enums[e.Name()]...)
+	for i, n := range named {
+		if n.Type() == e.Type() {
+			named[i] = e
+		}
+	}
+	r.name2enums[e.Name()] = named
+}
+
+// setDeprecated Mark or unmark the member as deprecated
+func (r *registry) setDeprecated(e EnumDefinition, deprecated bool) {
+	names := make(map[string]bool, len(r.deprecated[e.Type()])+1)
+	for k, v := range r.deprecated[e.Type()] {
+		names[k] = v
+	}
+	if deprecated {
+		names[e.Name()] = true
+	} else {
+		delete(names, e.Name())
+	}
+	r.deprecated[e.Type()] = names
+}
+
+// IsDeprecated Whether the enumeration has been removed from its dynamic definition by reloading.
+// Deprecated members are retained as tombstones: they keep their ordinals, and can still be found by ValueOf and Values,
+// so the existing references and serialized data keep working
+func IsDeprecated(e EnumDefinition) bool {
+	return defaultRegistry.IsDeprecated(e)
+}
+
+// IsDeprecated Whether the enumeration has been removed from its dynamic definition by reloading, see IsDeprecated
+func (reg *Registry) IsDeprecated(e EnumDefinition) bool {
+	for ; reg != nil; reg = reg.parent {
+		r := reg.load()
+		if r.find(e.Type(), e.Name()) != nil {
+			return r.deprecated[e.Type()][e.Name()]
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package goenum
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema A JSON Schema (and OpenAPI 3 schema object) fragment
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	EnumDescriptions     []string           `json:"x-enum-descriptions,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Components The components object of an OpenAPI 3 document, only schemas are generated
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+	// names The component name of each walked type, same-named types of different packages get different names
+	names map[reflect.Type]string
+}
+
+// describer Enumerations providing a description, such as internal.Code
+type describer interface {
+	Desc() string
+}
+
+// JSONSchema Generate the JSON Schema of the enumeration type T, such as {"type":"string","enum":["Created","Paid"]}.
+// Members are listed in ordinal order, if T has a Desc() string method,
+// the member descriptions are listed in x-enum-descriptions.
+func JSONSchema[T EnumDefinition]() *Schema {
+	return enumSchema(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// EnumSetJSONSchema Generate the JSON Schema of EnumSet[E], an array of unique names
+func EnumSetJSONSchema[E EnumDefinition]() *Schema {
+	return &Schema{Type: "array", Items: JSONSchema[E](), UniqueItems: true}
+}
+
+func enumSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "string"}
+	hasDesc := false
+	for _, e := range loadRegistry().type2enums[typeKey(t)] {
+		s.Enum = append(s.Enum, e.Name())
+		desc := ""
+		if d, ok := e.(describer); ok {
+			desc, hasDesc = d.Desc(), true
+		}
+		s.EnumDescriptions = append(s.EnumDescriptions, desc)
+	}
+	if !hasDesc {
+		s.EnumDescriptions = nil
+	}
+	return s
+}
+
+// OpenAPIComponents Walk the struct types of models and generate the OpenAPI components.
+// Each struct and each enumeration type becomes a named schema referenced by $ref,
+// EnumSet fields become arrays of unique enumeration names. Schema names are the qualified Go type names,
+// such as pkga.Status, a later type whose name is taken is named by its full package path instead.
+func OpenAPIComponents(models ...any) *Components {
+	c := &Components{Schemas: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	for _, m := range models {
+		c.schemaOf(reflect.TypeOf(m))
+	}
+	return c
+}
+
+// schemaOf Return the schema of t, named types are registered as components and referenced
+func (c *Components) schemaOf(t reflect.Type) *Schema {
+	if isEnumType(t) {
+		name := c.schemaName(t)
+		if _, exist := c.Schemas[name]; !exist {
+			c.Schemas[name] = enumSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	if binder, ok := reflect.Zero(t).Interface().(enumSetBinder); ok {
+		return &Schema{Type: "array", Items: c.schemaOf(binder.enumType()), UniqueItems: true}
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return c.schemaOf(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: c.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: c.schemaOf(t.Elem())}
+	case reflect.Struct:
+		name := c.schemaName(t)
+		if _, exist := c.Schemas[name]; !exist {
+			s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			// 先占位，防止自引用结构体无限递归
+			c.Schemas[name] = s
+			c.addProperties(s, t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// addProperties Add the exported fields of struct t as properties, named by the json tag
+func (c *Components) addProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if sf.PkgPath != "" || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr && !isEnumType(ft) {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && !isEnumType(ft) {
+			c.addProperties(s, ft)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		s.Properties[name] = c.schemaOf(sf.Type)
+	}
+}
+
+// schemaName Component name of t, the qualified Go type name without pointer.
+// The name is qualified by the full package path if another type has taken it, and numbered if still taken
+func (c *Components) schemaName(t reflect.Type) string {
+	if name, ok := c.names[t]; ok {
+		return name
+	}
+	name := strings.TrimLeft(typeKey(t), "*")
+	if c.Schemas[name] != nil {
+		base := t
+		for base.Name() == "" && base.Kind() == reflect.Ptr {
+			base = base.Elem()
+		}
+		name = sanitizeSchemaName(base.PkgPath()) + "." + base.Name()
+		for i, qualified := 2, name; c.Schemas[name] != nil; i++ {
+			name = qualified + "_" + strconv.Itoa(i)
+		}
+	}
+	c.names[t] = name
+	return name
+}
+
+// sanitizeSchemaName Replace the characters not allowed in component names, such as the / of package paths
+func sanitizeSchemaName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,34 @@
+package goenum
+
+import (
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAPIComponents_SameNamedTypes(t *testing.T) {
+	orderStatus := func() any {
+		type Status struct {
+			Paid bool `json:"paid"`
+		}
+		return Status{}
+	}()
+	shipStatus := func() any {
+		type Status struct {
+			Shipped bool `json:"shipped"`
+		}
+		return Status{}
+	}()
+	returnStatus := func() any {
+		type Status struct {
+			Returned bool `json:"returned"`
+		}
+		return Status{}
+	}()
+	c := OpenAPIComponents(orderStatus, shipStatus, returnStatus, orderStatus)
+	require.Len(t, c.Schemas, 3)
+	require.Contains(t, c.Schemas["goenum.Status"].Properties, "paid")
+	require.Contains(t, c.Schemas["github.com_lvyahui8_goenum.Status"].Properties, "shipped")
+	require.Contains(t, c.Schemas["github.com_lvyahui8_goenum.Status_2"].Properties, "returned")
+	require.Equal(t, "github.com_lvyahui8_goenum.Status", c.schemaName(reflect.TypeOf(shipStatus)))
+}
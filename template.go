@@ -0,0 +1,135 @@
+package goenum
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// FuncMap Template functions operating on the default registry, for both text/template and html/template.
+// Types are referred by qualified names such as "internal.TradeState", or unqualified names if not ambiguous:
+//   - enumValues "internal.TradeState" All members sorted by ordinal, such as {{range enumValues "TradeState"}}
+//   - enumNames "internal.TradeState" The names of all members
+//   - enumValueOf "internal.TradeState" "Paid" The member by name, case-insensitive
+//   - enumLabel .State The attribute label of the member, or its name if there is no such attribute,
+//     another attribute can be given, such as {{enumLabel .Code "desc"}}
+//   - enumAttr .State "isFinal" The attribute of the member, see Attributes
+//   - enumIs .State "Paid" "Shipped" Whether the member is one of the names or members
+//   - setHas .Perms "AddLabels" Whether the EnumSet contains the name or member
+//
+// Unknown types, members and attributes fail the execution of the template
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"enumValues":  templateValues,
+		"enumNames":   templateNames,
+		"enumValueOf": templateValueOf,
+		"enumLabel":   templateLabel,
+		"enumAttr":    templateAttr,
+		"enumIs":      templateIs,
+		"setHas":      templateSetHas,
+	}
+}
+
+func templateValues(typeName string) ([]EnumDefinition, error) {
+	key, ok := typeKeyOfName(typeName)
+	if !ok {
+		return nil, errors.New("goenum: unknown enumeration type " + strconv.Quote(typeName))
+	}
+	return append([]EnumDefinition(nil), loadRegistry().type2enums[key]...), nil
+}
+
+func templateNames(typeName string) ([]string, error) {
+	values, err := templateValues(typeName)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for _, e := range values {
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+func templateValueOf(typeName, name string) (EnumDefinition, error) {
+	values, err := templateValues(typeName)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range values {
+		if e.Name() == name {
+			return e, nil
+		}
+	}
+	for _, e := range values {
+		if strings.EqualFold(e.Name(), name) {
+			return e, nil
+		}
+	}
+	return nil, errors.New("goenum: " + strconv.Quote(name) + " is not a member of " + typeName)
+}
+
+func templateLabel(e EnumDefinition, attribute ...string) (any, error) {
+	if len(attribute) > 1 {
+		return nil, errors.New("goenum: enumLabel accepts at most one attribute")
+	}
+	name := "label"
+	if len(attribute) > 0 {
+		name = attribute[0]
+	}
+	if v, ok := attributeOf(e, name); ok {
+		return v, nil
+	}
+	if len(attribute) > 0 {
+		return nil, errors.New("goenum: " + e.Type() + " has no attribute " + name)
+	}
+	return e.Name(), nil
+}
+
+func templateAttr(e EnumDefinition, name string) (any, error) {
+	if v, ok := attributeOf(e, name); ok {
+		return v, nil
+	}
+	return nil, errors.New("goenum: " + e.Type() + " has no attribute " + name)
+}
+
+// templateIs candidates可以是名称或枚举实例
+func templateIs(e EnumDefinition, candidates ...any) (bool, error) {
+	for _, c := range candidates {
+		name, err := templateMemberName(c)
+		if err != nil {
+			return false, err
+		}
+		if e.Name() == name {
+			if other, ok := c.(EnumDefinition); ok && other.Type() != e.Type() {
+				continue
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func templateSetHas(set interface{ Names() []string }, member any) (bool, error) {
+	name, err := templateMemberName(member)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range set.Names() {
+		if n == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func templateMemberName(v any) (string, error) {
+	switch m := v.(type) {
+	case string:
+		return m, nil
+	case EnumDefinition:
+		return m.Name(), nil
+	}
+	return "", errors.New("goenum: expected a name or an enumeration instance, got " + fmt.Sprintf("%T", v))
+}
@@ -0,0 +1,327 @@
+package goenum
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidationError Aggregate all invalid fields found by Validate
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate Validate the fields of the struct v (or pointed to by v) according to the `goenum` struct tags.
+// The tag applies to fields of enumeration types, strings, and slices or pointers of them, options are comma-separated:
+//   - type=TradeState The enumeration type, required for string fields. Both "TradeState" and "internal.TradeState" are accepted
+//   - oneof=Paid|Shipped Only the listed members are allowed
+//   - required The field must not be the zero value or an empty string, otherwise empty fields are not checked
+//   - final, !final Any other option names a predicate method of the enumeration, such as IsFinal() bool or Final() bool,
+//     the member must satisfy it, the "!" prefix negates it
+//
+// Nested structs, slices and arrays are walked recursively, errors are qualified by field path such as Order.Items[0].State
+func Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.New("goenum: Validate requires a struct or a non-nil pointer to struct")
+	}
+	var fieldErrors []*FieldError
+	validateStruct(rv, rv.Type().Name(), &fieldErrors)
+	if len(fieldErrors) > 0 {
+		return &ValidationError{Errors: fieldErrors}
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, path string, fieldErrors *[]*FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		fieldPath := path + "." + sf.Name
+		if _, ok := sf.Tag.Lookup("goenum"); ok {
+			spec, err := validateSpecOf(t, sf)
+			if err != nil {
+				*fieldErrors = append(*fieldErrors, &FieldError{Field: fieldPath, Err: err})
+				continue
+			}
+			validateValue(v.Field(i), fieldPath, spec, fieldErrors)
+			continue
+		}
+		walkNested(v.Field(i), fieldPath, fieldErrors)
+	}
+}
+
+// walkNested Walk into the untagged struct, pointer, slice and array fields
+func walkNested(v reflect.Value, path string, fieldErrors *[]*FieldError) {
+	if isEnumType(v.Type()) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walkNested(v.Elem(), path, fieldErrors)
+		}
+	case reflect.Struct:
+		validateStruct(v, path, fieldErrors)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkNested(v.Index(i), path+"["+strconv.Itoa(i)+"]", fieldErrors)
+		}
+	}
+}
+
+// validateSpec Parsed `goenum` tag
+type validateSpec struct {
+	enumType string
+	required bool
+	// allowed Names of the members satisfying all options, sorted by ordinal
+	allowed []string
+}
+
+func (spec *validateSpec) isAllowed(name string) bool {
+	for _, n := range spec.allowed {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSpecKey The key of a tagged field in validateSpecs
+type validateSpecKey struct {
+	structType reflect.Type
+	field      string
+}
+
+// cachedValidateSpec The parsed tag of a field, the allowed members are computed from the snapshot
+type cachedValidateSpec struct {
+	snapshot *registry
+	spec     *validateSpec
+	err      error
+}
+
+// validateSpecs validateSpecKey -> *cachedValidateSpec
+var validateSpecs sync.Map
+
+// validateSpecOf The parsed `goenum` tag of the field sf of structType. The tag is parsed once,
+// and parsed again only after the registry changes, as the allowed members depend on it
+func validateSpecOf(structType reflect.Type, sf reflect.StructField) (*validateSpec, error) {
+	key := validateSpecKey{structType: structType, field: sf.Name}
+	snapshot := loadRegistry()
+	if v, ok := validateSpecs.Load(key); ok {
+		if cached := v.(*cachedValidateSpec); cached.snapshot == snapshot {
+			return cached.spec, cached.err
+		}
+	}
+	spec, err := parseValidateTag(sf.Tag.Get("goenum"), sf.Type, snapshot)
+	validateSpecs.Store(key, &cachedValidateSpec{snapshot: snapshot, spec: spec, err: err})
+	return spec, err
+}
+
+// parseValidateTag Parse the tag of a field of type t, and compute the allowed members
+func parseValidateTag(tag string, t reflect.Type, snapshot *registry) (*validateSpec, error) {
+	spec := &validateSpec{}
+	var oneof []string
+	type predicate struct {
+		name   string
+		negate bool
+	}
+	var predicates []predicate
+	for _, opt := range strings.Split(tag, ",") {
+		opt = strings.TrimSpace(opt)
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "":
+		case "type":
+			spec.enumType = value
+		case "oneof":
+			oneof = strings.Split(value, "|")
+		case "required":
+			spec.required = true
+		default:
+			if strings.HasPrefix(key, "!") {
+				predicates = append(predicates, predicate{name: key[1:], negate: true})
+			} else {
+				predicates = append(predicates, predicate{name: key})
+			}
+		}
+	}
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Array || (t.Kind() == reflect.Ptr && !isEnumType(t)) {
+		t = t.Elem()
+	}
+	key := typeKey(t)
+	if spec.enumType != "" {
+		k, ok := typeKeyOfName(spec.enumType)
+		if !ok {
+			return nil, errors.New("unknown enum type " + strconv.Quote(spec.enumType))
+		}
+		if t.Kind() != reflect.String && k != key {
+			return nil, errors.New("enum type " + strconv.Quote(spec.enumType) + " mismatches field type " + key)
+		}
+		key = k
+	} else if t.Kind() == reflect.String {
+		return nil, errors.New("type option is required for string fields")
+	} else if !isEnumType(t) {
+		return nil, errors.New("unsupported field type " + key)
+	}
+	spec.enumType = key
+	enums := snapshot.type2enums[key]
+	for _, name := range oneof {
+		if !containsName(enums, name) {
+			return nil, errors.New("unknown enum " + strconv.Quote(name) + " of " + key)
+		}
+	}
+	for _, e := range enums {
+		if len(oneof) > 0 && !containsString(oneof, e.Name()) {
+			continue
+		}
+		satisfied := true
+		for _, p := range predicates {
+			res, ok := callPredicate(e, p.name)
+			if !ok {
+				return nil, errors.New("unknown predicate " + strconv.Quote(p.name) + " of " + key)
+			}
+			if res == p.negate {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			spec.allowed = append(spec.allowed, e.Name())
+		}
+	}
+	return spec, nil
+}
+
+// callPredicate Call the method IsXxx() bool or Xxx() bool of the enumeration, the name is case-insensitive
+func callPredicate(e EnumDefinition, name string) (res bool, ok bool) {
+	v := reflect.ValueOf(e)
+	for i := 0; i < v.NumMethod(); i++ {
+		methodName := v.Type().Method(i).Name
+		if !strings.EqualFold(methodName, "Is"+name) && !strings.EqualFold(methodName, name) {
+			continue
+		}
+		m := v.Method(i)
+		if m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.Bool {
+			continue
+		}
+		return m.Call(nil)[0].Bool(), true
+	}
+	return false, false
+}
+
+func validateValue(v reflect.Value, path string, spec *validateSpec, fieldErrors *[]*FieldError) {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), path+"["+strconv.Itoa(i)+"]", spec, fieldErrors)
+		}
+		return
+	}
+	if fe := checkValue(v, spec); fe != nil {
+		fe.Field = path
+		*fieldErrors = append(*fieldErrors, fe)
+	}
+}
+
+// checkValue Check a single enumeration or string value, return nil if valid
+func checkValue(v reflect.Value, spec *validateSpec) *FieldError {
+	for v.Kind() == reflect.Ptr && !isEnumType(v.Type()) {
+		if v.IsNil() {
+			return checkName("", spec)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return checkName(v.String(), spec)
+	}
+	if v.IsZero() {
+		return checkName("", spec)
+	}
+	return checkName(v.Interface().(EnumDefinition).Name(), spec)
+}
+
+func checkName(name string, spec *validateSpec) *FieldError {
+	if name == "" {
+		if spec.required {
+			return &FieldError{Allowed: spec.allowed, Err: errors.New("required")}
+		}
+		return nil
+	}
+	if !spec.isAllowed(name) {
+		return &FieldError{Value: name, Allowed: spec.allowed, Err: errors.New("not allowed")}
+	}
+	return nil
+}
+
+// FieldLevel The subset of validator.FieldLevel (github.com/go-playground/validator/v10) used by RegisterValidation
+type FieldLevel interface {
+	// Field The current field to validate
+	Field() reflect.Value
+	// Parent The struct containing the current field
+	Parent() reflect.Value
+	// StructFieldName The Go name of the current field
+	StructFieldName() string
+}
+
+// RegisterValidation Register the "goenum" validation to github.com/go-playground/validator without depending on it:
+//
+//	validate := validator.New()
+//	err := goenum.RegisterValidation(validate.RegisterValidation)
+//
+// Fields tagged with `validate:"goenum"` are validated by their `goenum` struct tag, as Validate does.
+func RegisterValidation[F ~func(FL) bool, FL FieldLevel](register func(tag string, fn F, callValidationEvenIfNull ...bool) error) error {
+	return register("goenum", F(func(fl FL) bool {
+		parent := fl.Parent()
+		for parent.Kind() == reflect.Ptr {
+			parent = parent.Elem()
+		}
+		if parent.Kind() != reflect.Struct {
+			return false
+		}
+		sf, ok := parent.Type().FieldByName(fl.StructFieldName())
+		if !ok {
+			return false
+		}
+		spec, err := validateSpecOf(parent.Type(), sf)
+		if err != nil {
+			return false
+		}
+		var fieldErrors []*FieldError
+		validateValue(fl.Field(), sf.Name, spec, &fieldErrors)
+		return len(fieldErrors) == 0
+	}), true)
+}
+
+func containsName(enums []EnumDefinition, name string) bool {
+	for _, e := range enums {
+		if e.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
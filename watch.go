@@ -0,0 +1,129 @@
+package goenum
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ChangeEvent Changes of the members of an enumeration type made by loading or reloading dynamic definitions
+type ChangeEvent struct {
+	// Type The enumeration type, such as dynamic.Region
+	Type string
+	// Added New members, including the deprecated members defined again
+	Added []EnumDefinition
+	// Removed Members removed from the definition, they are retained as deprecated tombstones, see IsDeprecated
+	Removed []EnumDefinition
+	// Changed Members whose attributes are changed
+	Changed []MemberChange
+}
+
+// MemberChange The attributes of a member are changed
+type MemberChange struct {
+	// Old The instance before the change, New the instance registered now. They have the same name and ordinal
+	Old, New EnumDefinition
+	// Attributes Names of the changed attributes
+	Attributes []string
+}
+
+// IsEmpty Nothing is changed
+func (e *ChangeEvent) IsEmpty() bool {
+	return len(e.Added) == 0 && len(e.Removed) == 0 && len(e.Changed) == 0
+}
+
+type subscription struct {
+	ch     chan ChangeEvent
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+	once   sync.Once
+}
+
+func (s *subscription) send(event ChangeEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- event:
+	case <-s.done:
+	}
+}
+
+func (s *subscription) cancel() {
+	s.once.Do(func() {
+		// 先关闭done，唤醒阻塞在send上的发送方，再关闭ch
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
+
+// Watch Subscribe to the changes of the enumeration type T. Events are delivered in order, and the registry already
+// reflects the changes when an event is received. Subscribers must keep draining the channel, otherwise the
+// subsequent reloads of the type block, while the reloads of other types are not affected.
+// cancel unsubscribes and closes the channel.
+func Watch[T EnumDefinition]() (events <-chan ChangeEvent, cancel func()) {
+	return WatchIn[T](defaultRegistry)
+}
+
+// WatchIn Subscribe to the changes of the enumeration type T in the registry reg, see Watch.
+// The changes in the ancestors of reg are not delivered
+func WatchIn[T EnumDefinition](reg *Registry) (events <-chan ChangeEvent, cancel func()) {
+	tKey := typeKey(reflect.TypeOf((*T)(nil)).Elem())
+	sub := &subscription{ch: make(chan ChangeEvent, 16), done: make(chan struct{})}
+	reg.watchMu.Lock()
+	reg.watchers[tKey] = append(reg.watchers[tKey], sub)
+	reg.watchMu.Unlock()
+	return sub.ch, func() {
+		reg.watchMu.Lock()
+		subs := reg.watchers[tKey]
+		for i, s := range subs {
+			if s == sub {
+				reg.watchers[tKey] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		reg.watchMu.Unlock()
+		sub.cancel()
+	}
+}
+
+// delivery The tickets of the change events of a type: next is the ticket of the next event,
+// done the number of events delivered
+type delivery struct {
+	next, done uint64
+}
+
+// ticket Reserve the turn of the next change event of the type. Tickets are taken in the order the changes are published
+func (reg *Registry) ticket(tKey string) uint64 {
+	reg.watchMu.Lock()
+	defer reg.watchMu.Unlock()
+	d := reg.deliveries[tKey]
+	if d == nil {
+		d = &delivery{}
+		reg.deliveries[tKey] = d
+	}
+	d.next++
+	return d.next - 1
+}
+
+// notify Send the event to the subscribers of its type, after the events of the previous tickets of the type
+func (reg *Registry) notify(event ChangeEvent, ticket uint64) {
+	reg.watchMu.Lock()
+	d := reg.deliveries[event.Type]
+	for d.done != ticket {
+		reg.delivered.Wait()
+	}
+	subs := reg.watchers[event.Type]
+	reg.watchMu.Unlock()
+	for _, sub := range subs {
+		sub.send(event)
+	}
+	reg.watchMu.Lock()
+	d.done++
+	reg.watchMu.Unlock()
+	reg.delivered.Broadcast()
+}